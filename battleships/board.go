@@ -0,0 +1,267 @@
+package battleships
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ViolationCode tells which rule is broken
+type ViolationCode string
+
+const (
+	// board dimensions differ from the ruleset
+	InvalidSize ViolationCode = "invalid_size"
+	// cell is neither 0 nor 1
+	InvalidCell ViolationCode = "invalid_cell"
+	// ship is longer than the longest ship of the fleet
+	ShipTooLong ViolationCode = "ship_too_long"
+	// ships touch against the adjacency policy
+	ShipsTouch ViolationCode = "ships_touch"
+	// ship is neither straight nor an allowed L shape
+	InvalidShape ViolationCode = "invalid_shape"
+	// number of ships of a length differs from the fleet
+	WrongShipCount ViolationCode = "wrong_ship_count"
+)
+
+// Cell is a zero based position on a board
+type Cell struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
+
+func (c Cell) String() string {
+	return fmt.Sprintf("(%d, %d)", c.Row, c.Col)
+}
+
+// Violation is a broken rule with the cells breaking it. Length, Expected and
+// Actual are set for ship counts
+type Violation struct {
+	Code     ViolationCode `json:"code"`
+	Cells    []Cell        `json:"cells,omitempty"`
+	Length   int           `json:"length,omitempty"`
+	Expected int           `json:"expected,omitempty"`
+	Actual   int           `json:"actual,omitempty"`
+	Message  string        `json:"message"`
+}
+
+// BoardError lists all broken rules of a board
+type BoardError struct {
+	Violations []Violation `json:"violations"`
+}
+
+func (e *BoardError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Message
+	}
+	return "Invalid board: " + strings.Join(messages, "; ")
+}
+
+// ValidateBoard checks the board against the classic rules
+func ValidateBoard(board [][]int) error {
+	return ClassicRuleset().ValidateBoard(board)
+}
+
+// ValidateBoard checks the board against the rules and returns a
+// *BoardError with every violation found
+func (r *Ruleset) ValidateBoard(board [][]int) error {
+	if len(board) != r.Rows {
+		return &BoardError{[]Violation{{
+			Code:    InvalidSize,
+			Message: fmt.Sprintf("board has %d rows instead of %d", len(board), r.Rows),
+		}}}
+	}
+	var violations []Violation
+	for i, row := range board {
+		if len(row) != r.Cols {
+			violations = append(violations, Violation{
+				Code:    InvalidSize,
+				Message: fmt.Sprintf("row %d has %d cells instead of %d", i, len(row), r.Cols),
+			})
+		}
+	}
+	if violations != nil {
+		return &BoardError{violations}
+	}
+	for i, row := range board {
+		for j, cell := range row {
+			if cell != 0 && cell != 1 {
+				violations = append(violations, Violation{
+					Code:    InvalidCell,
+					Cells:   []Cell{{i, j}},
+					Message: fmt.Sprintf("cell (%d, %d) is %d instead of 0 or 1", i, j, cell),
+				})
+			}
+		}
+	}
+	if violations != nil {
+		return &BoardError{violations}
+	}
+
+	maxLength := r.maxShipLength()
+	counts := make(map[int]int)
+	for _, cells := range shipComponents(board, r.Adjacency == NoTouching) {
+		switch {
+		// cells of a single ship touch by sides and are not more than the longest ship,
+		// other bent groups are ships touching each other
+		case !straight(cells) && !(r.LShapes && lShaped(cells)) && len(cells) <= maxLength && sideConnected(cells):
+			violations = append(violations, Violation{
+				Code:    InvalidShape,
+				Cells:   cells,
+				Message: fmt.Sprintf("ship at %v has an invalid shape", cells),
+			})
+		case !straight(cells) && !(r.LShapes && lShaped(cells)):
+			violations = append(violations, Violation{
+				Code:    ShipsTouch,
+				Cells:   cells,
+				Message: fmt.Sprintf("ships at %v touch each other", cells),
+			})
+		case len(cells) > maxLength:
+			violations = append(violations, Violation{
+				Code:    ShipTooLong,
+				Cells:   cells,
+				Length:  len(cells),
+				Message: fmt.Sprintf("ship at %v is longer than %d", cells, maxLength),
+			})
+		default:
+			counts[len(cells)]++
+		}
+	}
+	for length := 1; length <= maxLength; length++ {
+		if counts[length] != r.Fleet[length] {
+			violations = append(violations, Violation{
+				Code:     WrongShipCount,
+				Length:   length,
+				Expected: r.Fleet[length],
+				Actual:   counts[length],
+				Message:  fmt.Sprintf("%d ships of length %d instead of %d", counts[length], length, r.Fleet[length]),
+			})
+		}
+	}
+	if violations != nil {
+		return &BoardError{violations}
+	}
+	return nil
+}
+
+// shipComponents groups occupied cells touching by a side, or by a corner
+// too if diagonal is set, cells of a group are sorted
+func shipComponents(board [][]int, diagonal bool) [][]Cell {
+	visited := make([][]bool, len(board))
+	for i := range visited {
+		visited[i] = make([]bool, len(board[i]))
+	}
+	var components [][]Cell
+	for i := range board {
+		for j := range board[i] {
+			if board[i][j] != 1 || visited[i][j] {
+				continue
+			}
+			visited[i][j] = true
+			stack := []Cell{{i, j}}
+			var cells []Cell
+			for len(stack) != 0 {
+				c := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				cells = append(cells, c)
+				for di := -1; di <= 1; di++ {
+					for dj := -1; dj <= 1; dj++ {
+						if di != 0 && dj != 0 && !diagonal {
+							continue
+						}
+						r, col := c.Row+di, c.Col+dj
+						if r < 0 || r >= len(board) || col < 0 || col >= len(board[r]) {
+							continue
+						}
+						if board[r][col] == 1 && !visited[r][col] {
+							visited[r][col] = true
+							stack = append(stack, Cell{r, col})
+						}
+					}
+				}
+			}
+			sort.Slice(cells, func(a, b int) bool {
+				if cells[a].Row != cells[b].Row {
+					return cells[a].Row < cells[b].Row
+				}
+				return cells[a].Col < cells[b].Col
+			})
+			components = append(components, cells)
+		}
+	}
+	return components
+}
+
+// sideConnected tells if cells are a single ship, every one of them reachable
+// from the first through cells touching by a side
+func sideConnected(cells []Cell) bool {
+	occupied := make(map[Cell]bool, len(cells))
+	for _, c := range cells {
+		occupied[c] = true
+	}
+	visited := map[Cell]bool{cells[0]: true}
+	stack := []Cell{cells[0]}
+	for len(stack) != 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, n := range []Cell{{c.Row - 1, c.Col}, {c.Row + 1, c.Col}, {c.Row, c.Col - 1}, {c.Row, c.Col + 1}} {
+			if occupied[n] && !visited[n] {
+				visited[n] = true
+				stack = append(stack, n)
+			}
+		}
+	}
+	return len(visited) == len(cells)
+}
+
+// straight tells if sorted cells form a horizontal or a vertical line
+func straight(cells []Cell) bool {
+	first := cells[0]
+	horizontal, vertical := true, true
+	for i, c := range cells {
+		horizontal = horizontal && c.Row == first.Row && c.Col == first.Col+i
+		vertical = vertical && c.Col == first.Col && c.Row == first.Row+i
+	}
+	return horizontal || vertical
+}
+
+// lShaped tells if cells form two straight arms of at least two cells
+// joined at a corner of their bounding box
+func lShaped(cells []Cell) bool {
+	minRow, maxRow, minCol, maxCol := cells[0].Row, cells[0].Row, cells[0].Col, cells[0].Col
+	occupied := make(map[Cell]bool, len(cells))
+	for _, c := range cells {
+		occupied[c] = true
+		if c.Row < minRow {
+			minRow = c.Row
+		}
+		if c.Row > maxRow {
+			maxRow = c.Row
+		}
+		if c.Col < minCol {
+			minCol = c.Col
+		}
+		if c.Col > maxCol {
+			maxCol = c.Col
+		}
+	}
+	height, width := maxRow-minRow+1, maxCol-minCol+1
+	if height < 2 || width < 2 || len(cells) != height+width-1 {
+		return false
+	}
+	// every cell is in the row or the column of the corner, so both arms are full
+	for _, corner := range []Cell{{minRow, minCol}, {minRow, maxCol}, {maxRow, minCol}, {maxRow, maxCol}} {
+		if !occupied[corner] {
+			continue
+		}
+		inArms := true
+		for _, c := range cells {
+			inArms = inArms && (c.Row == corner.Row || c.Col == corner.Col)
+		}
+		if inArms {
+			return true
+		}
+	}
+	return false
+}
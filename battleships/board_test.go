@@ -0,0 +1,125 @@
+package battleships
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// parseBoard reads rows of '0' and '1', other characters are kept as their digit values
+func parseBoard(rows ...string) [][]int {
+	board := make([][]int, len(rows))
+	for i, row := range rows {
+		board[i] = make([]int, len(row))
+		for j, c := range row {
+			board[i][j] = int(c - '0')
+		}
+	}
+	return board
+}
+
+var validBoard = []string{
+	"1111000000",
+	"0000000000",
+	"1110111000",
+	"0000000000",
+	"1101101100",
+	"0000000000",
+	"1010101000",
+	"0000000000",
+	"0000000000",
+	"0000000000",
+}
+
+// violations returns codes of the error violations
+func violations(t *testing.T, err error) []Violation {
+	if err == nil {
+		t.Fatal("invalid board is accepted")
+	}
+	boardErr, ok := err.(*BoardError)
+	if !ok {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return boardErr.Violations
+}
+
+func TestValidateBoard(t *testing.T) {
+	if err := ValidateBoard(parseBoard(validBoard...)); err != nil {
+		t.Fatal(err)
+	}
+
+	// vertical ships are fine too
+	transposed := make([]string, len(validBoard))
+	for i := range transposed {
+		var row strings.Builder
+		for j := range validBoard {
+			row.WriteByte(validBoard[j][i])
+		}
+		transposed[i] = row.String()
+	}
+	if err := ValidateBoard(parseBoard(transposed...)); err != nil {
+		t.Fatal(err)
+	}
+
+	v := violations(t, ValidateBoard(parseBoard(validBoard[:9]...)))
+	if len(v) != 1 || v[0].Code != InvalidSize {
+		t.Fatalf("unexpected violations %+v", v)
+	}
+	short := append(append([]string{}, validBoard[:9]...), "000")
+	if v = violations(t, ValidateBoard(parseBoard(short...))); len(v) != 1 || v[0].Code != InvalidSize {
+		t.Fatalf("unexpected violations %+v", v)
+	}
+
+	invalidCell := append([]string{}, validBoard...)
+	invalidCell[9] = "0000000020"
+	v = violations(t, ValidateBoard(parseBoard(invalidCell...)))
+	if len(v) != 1 || v[0].Code != InvalidCell || !reflect.DeepEqual(v[0].Cells, []Cell{{9, 8}}) {
+		t.Fatalf("unexpected violations %+v", v)
+	}
+}
+
+func TestBoardViolations(t *testing.T) {
+	// a 1x1 ship is moved to touch another one by a corner
+	diagonal := append([]string{}, validBoard...)
+	diagonal[6] = "1010100000"
+	diagonal[7] = "0000010000"
+	v := violations(t, ValidateBoard(parseBoard(diagonal...)))
+	expected := []Violation{{
+		Code:  ShipsTouch,
+		Cells: []Cell{{6, 4}, {7, 5}},
+	}, {
+		Code:     WrongShipCount,
+		Length:   1,
+		Expected: 4,
+		Actual:   2,
+	}}
+	for i := range v {
+		v[i].Message = ""
+	}
+	if !reflect.DeepEqual(v, expected) {
+		t.Fatalf("unexpected violations %+v", v)
+	}
+
+	// the 1x4 ship is bent, but touches no other ship
+	bent := append([]string{}, validBoard...)
+	bent[0] = "0000001110"
+	bent[1] = "0000000010"
+	v = violations(t, ValidateBoard(parseBoard(bent...)))
+	if len(v) != 2 || v[0].Code != InvalidShape || len(v[0].Cells) != 4 || v[1].Code != WrongShipCount {
+		t.Fatalf("unexpected violations %+v", v)
+	}
+
+	// a cell next to the 1x4 ship makes it too long
+	long := append([]string{}, validBoard...)
+	long[0] = "1111100000"
+	v = violations(t, ValidateBoard(parseBoard(long...)))
+	if len(v) != 2 || v[0].Code != ShipTooLong || v[0].Length != 5 ||
+		v[1].Code != WrongShipCount || v[1].Length != 4 || v[1].Actual != 0 {
+		t.Fatalf("unexpected violations %+v", v)
+	}
+
+	empty := parseBoard(strings.Split(strings.Repeat("0000000000 ", 10), " ")[:10]...)
+	if v = violations(t, ValidateBoard(empty)); len(v) != 4 {
+		t.Fatalf("unexpected violations %+v", v)
+	}
+}
@@ -0,0 +1,129 @@
+package battleships
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ClassicCircuit is the circuit of the libsnark battleship binary, it proves classic boards only
+const ClassicCircuit = "battleship"
+
+// AdjacencyPolicy tells how ships may touch each other
+type AdjacencyPolicy string
+
+const (
+	// ships never touch, not even by a corner
+	NoTouching AdjacencyPolicy = "none"
+	// ships may touch by a corner but not by a side
+	CornersAllowed AdjacencyPolicy = "corners"
+)
+
+var ErrInvalidRuleset = errors.New("Invalid ruleset")
+
+// Ruleset describes a variant of the game. Boards are Rows x Cols, Fleet maps
+// a ship length to the number of such ships and boards are proved by Circuit
+type Ruleset struct {
+	Name      string          `json:"name" yaml:"name"`
+	Rows      int             `json:"rows" yaml:"rows"`
+	Cols      int             `json:"cols" yaml:"cols"`
+	Fleet     map[int]int     `json:"fleet" yaml:"fleet"`
+	Adjacency AdjacencyPolicy `json:"adjacency" yaml:"adjacency"`
+	// LShapes allows ships of two straight arms joined at a corner
+	LShapes bool   `json:"lShapes" yaml:"lShapes"`
+	Circuit string `json:"circuit" yaml:"circuit"`
+}
+
+// ClassicRuleset returns the rules of the README: a 10x10 board with four
+// 1x1, three 1x2, two 1x3 and one 1x4 ships, none of them touching
+func ClassicRuleset() *Ruleset {
+	return &Ruleset{
+		Name:      "classic",
+		Rows:      10,
+		Cols:      10,
+		Fleet:     map[int]int{1: 4, 2: 3, 3: 2, 4: 1},
+		Adjacency: NoTouching,
+		Circuit:   ClassicCircuit,
+	}
+}
+
+// LoadRuleset reads a ruleset from a YAML file if it has a .yaml or .yml
+// extension and from a JSON file otherwise, unknown fields are rejected in
+// both. Adjacency defaults to NoTouching
+func LoadRuleset(filename string) (*Ruleset, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	r := new(Ruleset)
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(content, r)
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(r)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r.Adjacency == "" {
+		r.Adjacency = NoTouching
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Validate checks that the ruleset describes a playable game
+func (r *Ruleset) Validate() error {
+	if r.Rows <= 0 || r.Cols <= 0 {
+		return fmt.Errorf("%w: board is %dx%d", ErrInvalidRuleset, r.Rows, r.Cols)
+	}
+	if r.Adjacency != NoTouching && r.Adjacency != CornersAllowed {
+		return fmt.Errorf("%w: unknown adjacency %q", ErrInvalidRuleset, r.Adjacency)
+	}
+	if r.Circuit == "" {
+		return fmt.Errorf("%w: missing circuit", ErrInvalidRuleset)
+	}
+	longest := r.Rows
+	if r.Cols > longest {
+		longest = r.Cols
+	}
+	if r.LShapes {
+		longest = r.Rows + r.Cols - 1
+	}
+	cells, ships := 0, 0
+	for length, count := range r.Fleet {
+		if length <= 0 || length > longest || count < 0 {
+			return fmt.Errorf("%w: %d ships of length %d", ErrInvalidRuleset, count, length)
+		}
+		cells += length * count
+		ships += count
+	}
+	if ships == 0 {
+		return fmt.Errorf("%w: empty fleet", ErrInvalidRuleset)
+	}
+	if cells > r.Rows*r.Cols {
+		return fmt.Errorf("%w: fleet of %d cells does not fit the board", ErrInvalidRuleset, cells)
+	}
+	return nil
+}
+
+// maxShipLength returns the length of the longest ship of the fleet
+func (r *Ruleset) maxShipLength() int {
+	longest := 0
+	for length, count := range r.Fleet {
+		if count > 0 && length > longest {
+			longest = length
+		}
+	}
+	return longest
+}
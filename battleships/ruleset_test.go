@@ -0,0 +1,119 @@
+package battleships
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const smallRulesetYAML = `
+name: small
+rows: 4
+cols: 4
+fleet:
+  1: 2
+  3: 1
+adjacency: corners
+circuit: battleship-4x4
+`
+
+const lShapesRulesetJSON = `{
+	"name": "l-shapes",
+	"rows": 5,
+	"cols": 5,
+	"fleet": {"3": 1, "4": 1},
+	"lShapes": true,
+	"circuit": "battleship-l"
+}`
+
+func loadRuleset(t *testing.T, name, content string) (*Ruleset, error) {
+	dir, err := ioutil.TempDir("", "ruleset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return LoadRuleset(filename)
+}
+
+func TestLoadRuleset(t *testing.T) {
+	small, err := loadRuleset(t, "small.yaml", smallRulesetYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if small.Rows != 4 || small.Cols != 4 || small.Fleet[3] != 1 || small.Adjacency != CornersAllowed || small.Circuit != "battleship-4x4" {
+		t.Fatalf("unexpected ruleset %+v", small)
+	}
+	lShapes, err := loadRuleset(t, "l.json", lShapesRulesetJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lShapes.LShapes || lShapes.Fleet[4] != 1 || lShapes.Adjacency != NoTouching {
+		t.Fatalf("unexpected ruleset %+v", lShapes)
+	}
+	if err := ClassicRuleset().Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, content := range map[string]string{
+		"empty.json":     `{"rows": 4, "cols": 4, "fleet": {}, "circuit": "c"}`,
+		"size.json":      `{"rows": 0, "cols": 4, "fleet": {"1": 1}, "circuit": "c"}`,
+		"long.json":      `{"rows": 2, "cols": 2, "fleet": {"3": 1}, "circuit": "c"}`,
+		"crowded.json":   `{"rows": 2, "cols": 2, "fleet": {"1": 5}, "circuit": "c"}`,
+		"adjacency.json": `{"rows": 2, "cols": 2, "fleet": {"1": 1}, "adjacency": "sides", "circuit": "c"}`,
+		"circuit.json":   `{"rows": 2, "cols": 2, "fleet": {"1": 1}}`,
+	} {
+		if _, err := loadRuleset(t, name, content); !errors.Is(err, ErrInvalidRuleset) {
+			t.Fatalf("%s: expected %v, got %v", name, ErrInvalidRuleset, err)
+		}
+	}
+	if _, err := loadRuleset(t, "typo.yml", smallRulesetYAML+"fleets: {}\n"); err == nil {
+		t.Fatal("unknown field is accepted")
+	}
+	typo := strings.Replace(lShapesRulesetJSON, `"lShapes"`, `"lShape"`, 1)
+	if _, err := loadRuleset(t, "typo.json", typo); err == nil {
+		t.Fatal("unknown field is accepted")
+	}
+}
+
+func TestRulesetVariants(t *testing.T) {
+	small, _ := loadRuleset(t, "small.yaml", smallRulesetYAML)
+	// ships touching by a corner are fine, by a side are not
+	if err := small.ValidateBoard(parseBoard("1110", "0001", "0000", "1000")); err != nil {
+		t.Fatal(err)
+	}
+	v := violations(t, small.ValidateBoard(parseBoard("1110", "0010", "0000", "1000")))
+	if len(v) != 3 || v[0].Code != ShipsTouch {
+		t.Fatalf("unexpected violations %+v", v)
+	}
+	if v = violations(t, small.ValidateBoard(parseBoard(validBoard...))); v[0].Code != InvalidSize {
+		t.Fatalf("unexpected violations %+v", v)
+	}
+
+	lShapes, _ := loadRuleset(t, "l.json", lShapesRulesetJSON)
+	if err := lShapes.ValidateBoard(parseBoard("11000", "01000", "00000", "00100", "11100")); err != nil {
+		t.Fatal(err)
+	}
+	// a T is not an L, and L ships still keep away from each other
+	for board, code := range map[[5]string]ViolationCode{
+		{"11100", "01000", "00000", "00000", "01110"}: InvalidShape,
+		{"11000", "01000", "00100", "00100", "00110"}: ShipsTouch,
+	} {
+		v = violations(t, lShapes.ValidateBoard(parseBoard(board[:]...)))
+		if v[0].Code != code {
+			t.Fatalf("unexpected violations %+v", v)
+		}
+	}
+	// classic rules reject the L ship
+	classic := ClassicRuleset()
+	classic.Rows, classic.Cols = 5, 5
+	if v = violations(t, classic.ValidateBoard(parseBoard("11000", "01000", "00000", "00100", "11100"))); v[0].Code != InvalidShape {
+		t.Fatalf("unexpected violations %+v", v)
+	}
+}
@@ -0,0 +1,12 @@
+package battleships
+
+import "github.com/shamatar/go-snarks/verifier"
+
+// LoadVerifyingKey reads the key of the battleship circuit written by libsnark, like vk_key.txt
+func LoadVerifyingKey(filename string) (*verifier.VerifyingKey, error) {
+	libsnarkVK := new(verifier.LibsnarkVerifyingKey)
+	if err := libsnarkVK.ParseFromFile(filename); err != nil {
+		return nil, err
+	}
+	return libsnarkVK.ToVerifyingKey()
+}
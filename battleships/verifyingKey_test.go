@@ -0,0 +1,16 @@
+package battleships
+
+import "testing"
+
+func TestLoadVerifyingKey(t *testing.T) {
+	vk, err := LoadVerifyingKey("../vk_key.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vk.IC) == 0 {
+		t.Fatal("key has no IC points")
+	}
+	if _, err := LoadVerifyingKey("missing.txt"); err == nil {
+		t.Fatal("missing key is loaded")
+	}
+}
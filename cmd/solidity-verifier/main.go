@@ -0,0 +1,69 @@
+// Command solidity-verifier writes a Solidity contract that verifies proofs
+// against a verifying key, so the on-chain verifier uses the same key as the backend
+//
+//	go run ./cmd/solidity-verifier -key vk_key.txt -out Verifier.sol
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/shamatar/go-snarks/verifier"
+)
+
+func loadVerifyingKey(filename, format string) (*verifier.VerifyingKey, error) {
+	switch format {
+	case "libsnark":
+		libsnarkVK := new(verifier.LibsnarkVerifyingKey)
+		if err := libsnarkVK.ParseFromFile(filename); err != nil {
+			return nil, err
+		}
+		return libsnarkVK.ToVerifyingKey()
+	case "zokrates":
+		r, err := os.Open(filename)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return verifier.ParseZoKratesVerifyingKey(r)
+	case "json":
+		content, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		vk := new(verifier.VerifyingKey)
+		if err := vk.UnmarshalJSON(content); err != nil {
+			return nil, err
+		}
+		return vk, nil
+	}
+	return nil, fmt.Errorf("Unknown key format %q", format)
+}
+
+func main() {
+	keyFile := flag.String("key", "vk_key.txt", "verifying key file")
+	format := flag.String("format", "libsnark", "key format: libsnark, zokrates or json")
+	name := flag.String("name", "Verifier", "name of the contract")
+	out := flag.String("out", "", "output file, stdout if empty")
+	flag.Parse()
+
+	vk, err := loadVerifyingKey(*keyFile, *format)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var contract bytes.Buffer
+	if err := verifier.WriteSolidityVerifier(&contract, vk, *name); err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		os.Stdout.Write(contract.Bytes())
+		return
+	}
+	if err := ioutil.WriteFile(*out, contract.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,120 @@
+package curve
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+)
+
+// BLS12-381 points are marshalled uncompressed without zcash flags:
+// 48 bytes big-endian coordinates, G2 coordinates are written as
+// x.c1, x.c0, y.c1, y.c0. Unlike BN254, G1 has a cofactor, so both
+// groups are checked for the subgroup.
+// Group engines of bls12381 keep temporary values, so a new one is made for every call
+
+const (
+	bls12381G1Size = 96
+	bls12381G2Size = 192
+)
+
+// BLS12381 is the curve of Ethereum 2.0 implemented by go-ethereum bls12381
+var BLS12381 Curve = bls12381Curve{}
+
+type blsG1 struct {
+	p *bls12381.PointG1
+}
+
+func (p blsG1) Marshal() []byte {
+	return bls12381.NewG1().ToBytes(new(bls12381.PointG1).Set(p.p))
+}
+
+type blsG2 struct {
+	p *bls12381.PointG2
+}
+
+func (p blsG2) Marshal() []byte {
+	return bls12381.NewG2().ToBytes(new(bls12381.PointG2).Set(p.p))
+}
+
+type bls12381Curve struct{}
+
+func (bls12381Curve) Name() string    { return "bls12-381" }
+func (bls12381Curve) Order() *big.Int { return bls12381.NewG1().Q() }
+func (bls12381Curve) G1Size() int     { return bls12381G1Size }
+func (bls12381Curve) G2Size() int     { return bls12381G2Size }
+func (bls12381Curve) G1Generator() G1 { return blsG1{bls12381.NewG1().One()} }
+func (bls12381Curve) G2Generator() G2 { return blsG2{bls12381.NewG2().One()} }
+
+func (bls12381Curve) IsG1(p G1) bool {
+	point, ok := p.(blsG1)
+	return ok && point.p != nil
+}
+
+func (bls12381Curve) IsG2(p G2) bool {
+	point, ok := p.(blsG2)
+	return ok && point.p != nil
+}
+
+func (bls12381Curve) AddG1(a, b G1) G1 {
+	g := bls12381.NewG1()
+	return blsG1{g.Add(g.New(), a.(blsG1).p, b.(blsG1).p)}
+}
+
+func (bls12381Curve) NegG1(a G1) G1 {
+	g := bls12381.NewG1()
+	return blsG1{g.Neg(g.New(), a.(blsG1).p)}
+}
+
+func (bls12381Curve) ScalarMultG1(a G1, k *big.Int) G1 {
+	g := bls12381.NewG1()
+	return blsG1{g.MulScalar(g.New(), a.(blsG1).p, k)}
+}
+
+func (bls12381Curve) AddG2(a, b G2) G2 {
+	g := bls12381.NewG2()
+	return blsG2{g.Add(g.New(), a.(blsG2).p, b.(blsG2).p)}
+}
+
+func (bls12381Curve) ScalarMultG2(a G2, k *big.Int) G2 {
+	g := bls12381.NewG2()
+	return blsG2{g.MulScalar(g.New(), a.(blsG2).p, k)}
+}
+
+func (bls12381Curve) UnmarshalG1(data []byte) (G1, error) {
+	if len(data) != bls12381G1Size {
+		return nil, ErrInvalidLength
+	}
+	g := bls12381.NewG1()
+	p, err := g.FromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	if !g.InCorrectSubgroup(p) {
+		return nil, ErrNotInSubgroup
+	}
+	return blsG1{p}, nil
+}
+
+func (bls12381Curve) UnmarshalG2(data []byte) (G2, error) {
+	if len(data) != bls12381G2Size {
+		return nil, ErrInvalidLength
+	}
+	g := bls12381.NewG2()
+	p, err := g.FromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	if !g.InCorrectSubgroup(p) {
+		return nil, ErrNotInSubgroup
+	}
+	return blsG2{p}, nil
+}
+
+func (bls12381Curve) PairingCheck(a []G1, b []G2) bool {
+	engine := bls12381.NewPairingEngine()
+	for i := range a {
+		// the engine converts points to affine in place
+		engine.AddPair(new(bls12381.PointG1).Set(a[i].(blsG1).p), new(bls12381.PointG2).Set(b[i].(blsG2).p))
+	}
+	return engine.Check()
+}
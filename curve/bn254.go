@@ -0,0 +1,166 @@
+package curve
+
+import (
+	"math/big"
+
+	cloudflare "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	google "github.com/ethereum/go-ethereum/crypto/bn256/google"
+)
+
+// BN254 points are marshalled as in EIP-197: 32 bytes big-endian coordinates,
+// G2 coordinates are written as x.c1, x.c0, y.c1, y.c0. Both implementations
+// check the subgroup in Unmarshal
+
+const (
+	bn254G1Size = 64
+	bn254G2Size = 128
+)
+
+// BN254 is the curve of Ethereum precompiles implemented by cloudflare bn256,
+// the same implementation the verifier package uses by default
+var BN254 Curve = bn254Cloudflare{}
+
+// BN254Google is the same curve implemented by google bn256, which is much slower
+var BN254Google Curve = bn254Google{}
+
+type bn254Cloudflare struct{}
+
+func (bn254Cloudflare) Name() string    { return "bn254" }
+func (bn254Cloudflare) Order() *big.Int { return new(big.Int).Set(cloudflare.Order) }
+func (bn254Cloudflare) G1Size() int     { return bn254G1Size }
+func (bn254Cloudflare) G2Size() int     { return bn254G2Size }
+func (bn254Cloudflare) G1Generator() G1 { return new(cloudflare.G1).ScalarBaseMult(big.NewInt(1)) }
+func (bn254Cloudflare) G2Generator() G2 { return new(cloudflare.G2).ScalarBaseMult(big.NewInt(1)) }
+
+func (bn254Cloudflare) IsG1(p G1) bool {
+	point, ok := p.(*cloudflare.G1)
+	return ok && point != nil
+}
+
+func (bn254Cloudflare) IsG2(p G2) bool {
+	point, ok := p.(*cloudflare.G2)
+	return ok && point != nil
+}
+
+func (bn254Cloudflare) AddG1(a, b G1) G1 {
+	return new(cloudflare.G1).Add(a.(*cloudflare.G1), b.(*cloudflare.G1))
+}
+
+func (bn254Cloudflare) NegG1(a G1) G1 {
+	return new(cloudflare.G1).Neg(a.(*cloudflare.G1))
+}
+
+func (bn254Cloudflare) AddG2(a, b G2) G2 {
+	return new(cloudflare.G2).Add(a.(*cloudflare.G2), b.(*cloudflare.G2))
+}
+
+func (bn254Cloudflare) ScalarMultG1(a G1, k *big.Int) G1 {
+	return new(cloudflare.G1).ScalarMult(a.(*cloudflare.G1), k)
+}
+
+func (bn254Cloudflare) ScalarMultG2(a G2, k *big.Int) G2 {
+	return new(cloudflare.G2).ScalarMult(a.(*cloudflare.G2), k)
+}
+
+func (bn254Cloudflare) UnmarshalG1(data []byte) (G1, error) {
+	if len(data) != bn254G1Size {
+		return nil, ErrInvalidLength
+	}
+	p := new(cloudflare.G1)
+	if _, err := p.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (bn254Cloudflare) UnmarshalG2(data []byte) (G2, error) {
+	if len(data) != bn254G2Size {
+		return nil, ErrInvalidLength
+	}
+	p := new(cloudflare.G2)
+	if _, err := p.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (bn254Cloudflare) PairingCheck(a []G1, b []G2) bool {
+	g1 := make([]*cloudflare.G1, len(a))
+	g2 := make([]*cloudflare.G2, len(b))
+	for i := range a {
+		g1[i] = a[i].(*cloudflare.G1)
+		g2[i] = b[i].(*cloudflare.G2)
+	}
+	return cloudflare.PairingCheck(g1, g2)
+}
+
+type bn254Google struct{}
+
+func (bn254Google) Name() string    { return "bn254-google" }
+func (bn254Google) Order() *big.Int { return new(big.Int).Set(google.Order) }
+func (bn254Google) G1Size() int     { return bn254G1Size }
+func (bn254Google) G2Size() int     { return bn254G2Size }
+func (bn254Google) G1Generator() G1 { return new(google.G1).ScalarBaseMult(big.NewInt(1)) }
+func (bn254Google) G2Generator() G2 { return new(google.G2).ScalarBaseMult(big.NewInt(1)) }
+
+func (bn254Google) IsG1(p G1) bool {
+	point, ok := p.(*google.G1)
+	return ok && point != nil
+}
+
+func (bn254Google) IsG2(p G2) bool {
+	point, ok := p.(*google.G2)
+	return ok && point != nil
+}
+
+func (bn254Google) AddG1(a, b G1) G1 {
+	return new(google.G1).Add(a.(*google.G1), b.(*google.G1))
+}
+
+func (bn254Google) NegG1(a G1) G1 {
+	return new(google.G1).Neg(a.(*google.G1))
+}
+
+func (bn254Google) AddG2(a, b G2) G2 {
+	return new(google.G2).Add(a.(*google.G2), b.(*google.G2))
+}
+
+func (bn254Google) ScalarMultG1(a G1, k *big.Int) G1 {
+	return new(google.G1).ScalarMult(a.(*google.G1), k)
+}
+
+func (bn254Google) ScalarMultG2(a G2, k *big.Int) G2 {
+	return new(google.G2).ScalarMult(a.(*google.G2), k)
+}
+
+func (bn254Google) UnmarshalG1(data []byte) (G1, error) {
+	if len(data) != bn254G1Size {
+		return nil, ErrInvalidLength
+	}
+	p := new(google.G1)
+	if _, err := p.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (bn254Google) UnmarshalG2(data []byte) (G2, error) {
+	if len(data) != bn254G2Size {
+		return nil, ErrInvalidLength
+	}
+	p := new(google.G2)
+	if _, err := p.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (bn254Google) PairingCheck(a []G1, b []G2) bool {
+	g1 := make([]*google.G1, len(a))
+	g2 := make([]*google.G2, len(b))
+	for i := range a {
+		g1[i] = a[i].(*google.G1)
+		g2[i] = b[i].(*google.G2)
+	}
+	return google.PairingCheck(g1, g2)
+}
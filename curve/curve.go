@@ -0,0 +1,68 @@
+// Package curve abstracts pairing friendly curves, so verifiers can run on
+// different curves and implementations of the same curve
+package curve
+
+import (
+	"errors"
+	"math/big"
+)
+
+// G1 is a point of the first group made by some Curve.
+// Points may only be passed back to the Curve that has made them
+type G1 interface {
+	// Marshal encodes the point as Curve.UnmarshalG1 expects,
+	// the point at infinity is all zeroes
+	Marshal() []byte
+}
+
+// G2 is a point of the second group made by some Curve
+type G2 interface {
+	// Marshal encodes the point as Curve.UnmarshalG2 expects,
+	// the point at infinity is all zeroes
+	Marshal() []byte
+}
+
+// Curve is a pairing friendly curve with its G1, G2 groups and a pairing
+type Curve interface {
+	// Name tells the curve and the implementation
+	Name() string
+	// Order is the order of G1 and G2, so scalars are integers mod Order
+	Order() *big.Int
+	// G1Size and G2Size are lengths of marshalled points
+	G1Size() int
+	G2Size() int
+
+	G1Generator() G1
+	G2Generator() G2
+
+	AddG1(a, b G1) G1
+	NegG1(a G1) G1
+	ScalarMultG1(a G1, k *big.Int) G1
+	AddG2(a, b G2) G2
+	ScalarMultG2(a G2, k *big.Int) G2
+
+	// IsG1 and IsG2 tell if the point is made by the curve, so it may be passed back
+	IsG1(p G1) bool
+	IsG2(p G2) bool
+
+	// UnmarshalG1 and UnmarshalG2 only accept points of the prime order subgroups
+	UnmarshalG1(data []byte) (G1, error)
+	UnmarshalG2(data []byte) (G2, error)
+
+	// PairingCheck tells if the product of e(a[i], b[i]) is one
+	PairingCheck(a []G1, b []G2) bool
+}
+
+var (
+	// ErrInvalidLength is returned if marshalled point has a wrong length
+	ErrInvalidLength = errors.New("Invalid length of the point")
+	// ErrNotInSubgroup is returned if point is on the curve, but not in the prime order subgroup
+	ErrNotInSubgroup = errors.New("Point is not in the prime order subgroup")
+)
+
+// Curves lists all the implementations by names
+var Curves = map[string]Curve{
+	BN254.Name():       BN254,
+	BN254Google.Name(): BN254Google,
+	BLS12381.Name():    BLS12381,
+}
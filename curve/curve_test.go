@@ -0,0 +1,96 @@
+package curve
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func randomScalar(t *testing.T, c Curve) *big.Int {
+	k, err := rand.Int(rand.Reader, c.Order())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestCurves(t *testing.T) {
+	for name, c := range Curves {
+		g1, g2 := c.G1Generator(), c.G2Generator()
+		if len(g1.Marshal()) != c.G1Size() || len(g2.Marshal()) != c.G2Size() {
+			t.Fatalf("%s: wrong size of marshalled points", name)
+		}
+
+		a, b := randomScalar(t, c), randomScalar(t, c)
+		aG1 := c.ScalarMultG1(g1, a)
+		bG2 := c.ScalarMultG2(g2, b)
+		parsedG1, err := c.UnmarshalG1(aG1.Marshal())
+		if err != nil || !bytes.Equal(parsedG1.Marshal(), aG1.Marshal()) {
+			t.Fatalf("%s: G1 round trip has failed: %v", name, err)
+		}
+		parsedG2, err := c.UnmarshalG2(bG2.Marshal())
+		if err != nil || !bytes.Equal(parsedG2.Marshal(), bG2.Marshal()) {
+			t.Fatalf("%s: G2 round trip has failed: %v", name, err)
+		}
+
+		// (a + b) * G == a * G + b * G
+		sumG1 := c.ScalarMultG1(g1, new(big.Int).Add(a, b))
+		if !bytes.Equal(sumG1.Marshal(), c.AddG1(aG1, c.ScalarMultG1(g1, b)).Marshal()) {
+			t.Fatalf("%s: G1 addition is wrong", name)
+		}
+		sumG2 := c.ScalarMultG2(g2, new(big.Int).Add(a, b))
+		if !bytes.Equal(sumG2.Marshal(), c.AddG2(c.ScalarMultG2(g2, a), bG2).Marshal()) {
+			t.Fatalf("%s: G2 addition is wrong", name)
+		}
+		infinity := c.ScalarMultG1(g1, c.Order())
+		if !bytes.Equal(infinity.Marshal(), make([]byte, c.G1Size())) {
+			t.Fatalf("%s: Order * G1 is not infinity", name)
+		}
+		if _, err := c.UnmarshalG1(infinity.Marshal()); err != nil {
+			t.Fatalf("%s: infinity is rejected: %v", name, err)
+		}
+
+		// e(a * G1, b * G2) * e(-(a * b) * G1, G2) == 1
+		ab := new(big.Int).Mul(a, b)
+		if !c.PairingCheck([]G1{aG1, c.NegG1(c.ScalarMultG1(g1, ab))}, []G2{bG2, g2}) {
+			t.Fatalf("%s: pairing is not bilinear", name)
+		}
+		if c.PairingCheck([]G1{aG1, c.NegG1(aG1)}, []G2{bG2, g2}) {
+			t.Fatalf("%s: invalid pairing check has passed", name)
+		}
+
+		if _, err := c.UnmarshalG1(aG1.Marshal()[1:]); err != ErrInvalidLength {
+			t.Fatalf("%s: expected %v, got %v", name, ErrInvalidLength, err)
+		}
+		if _, err := c.UnmarshalG2(append(bG2.Marshal(), 0)); err != ErrInvalidLength {
+			t.Fatalf("%s: expected %v, got %v", name, ErrInvalidLength, err)
+		}
+		notOnCurve := aG1.Marshal()
+		notOnCurve[len(notOnCurve)-1] ^= 1
+		if _, err := c.UnmarshalG1(notOnCurve); err == nil {
+			t.Fatalf("%s: point not on the curve is accepted", name)
+		}
+	}
+}
+
+func TestBN254Implementations(t *testing.T) {
+	k := randomScalar(t, BN254)
+	for _, pair := range [][2]Curve{{BN254, BN254Google}, {BN254Google, BN254}} {
+		from, to := pair[0], pair[1]
+		p1 := from.ScalarMultG1(from.G1Generator(), k)
+		p2 := from.ScalarMultG2(from.G2Generator(), k)
+		q1, err := to.UnmarshalG1(p1.Marshal())
+		if err != nil {
+			t.Fatal(err)
+		}
+		q2, err := to.UnmarshalG2(p2.Marshal())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(q1.Marshal(), to.ScalarMultG1(to.G1Generator(), k).Marshal()) ||
+			!bytes.Equal(q2.Marshal(), to.ScalarMultG2(to.G2Generator(), k).Marshal()) {
+			t.Fatalf("%s and %s disagree", from.Name(), to.Name())
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package bn256
+
+// lineCoefficients are the coefficients of a line of the Miller loop, b and c
+// are not multiplied yet by the x and y of the G1 point.
+type lineCoefficients struct {
+	a, b, c gfP2
+}
+
+// G2Prepared is a G2 point with the lines of its Miller loop computed in
+// advance, so a pairing with it only evaluates them at the G1 point.
+type G2Prepared struct {
+	lines    []lineCoefficients
+	infinity bool
+}
+
+// unitPoint makes the line functions return b and c without the scaling by
+// the G1 point.
+var unitPoint = &curvePoint{x: *newGFp(1), y: *newGFp(1), z: *newGFp(1), t: *newGFp(1)}
+
+// Prepare computes the lines of the Miller loop of e, in the order miller
+// multiplies them.
+func (e *G2) Prepare() *G2Prepared {
+	if e.p.IsInfinity() {
+		return &G2Prepared{infinity: true}
+	}
+	prepared := &G2Prepared{}
+	addLine := func(a, b, c *gfP2) {
+		prepared.lines = append(prepared.lines, lineCoefficients{*a, *b, *c})
+	}
+
+	aAffine := &twistPoint{}
+	aAffine.Set(e.p)
+	aAffine.MakeAffine()
+
+	minusA := &twistPoint{}
+	minusA.Neg(aAffine)
+
+	r := &twistPoint{}
+	r.Set(aAffine)
+
+	r2 := (&gfP2{}).Square(&aAffine.y)
+
+	for i := len(sixuPlus2NAF) - 1; i > 0; i-- {
+		a, b, c, newR := lineFunctionDouble(r, unitPoint)
+		addLine(a, b, c)
+		r = newR
+
+		switch sixuPlus2NAF[i-1] {
+		case 1:
+			a, b, c, newR = lineFunctionAdd(r, aAffine, unitPoint, r2)
+		case -1:
+			a, b, c, newR = lineFunctionAdd(r, minusA, unitPoint, r2)
+		default:
+			continue
+		}
+
+		addLine(a, b, c)
+		r = newR
+	}
+
+	// See miller for Q1 and -Q2.
+	q1 := &twistPoint{}
+	q1.x.Conjugate(&aAffine.x).Mul(&q1.x, xiToPMinus1Over3)
+	q1.y.Conjugate(&aAffine.y).Mul(&q1.y, xiToPMinus1Over2)
+	q1.z.SetOne()
+	q1.t.SetOne()
+
+	minusQ2 := &twistPoint{}
+	minusQ2.x.MulScalar(&aAffine.x, xiToPSquaredMinus1Over3)
+	minusQ2.y.Set(&aAffine.y)
+	minusQ2.z.SetOne()
+	minusQ2.t.SetOne()
+
+	r2.Square(&q1.y)
+	a, b, c, newR := lineFunctionAdd(r, q1, unitPoint, r2)
+	addLine(a, b, c)
+	r = newR
+
+	r2.Square(&minusQ2.y)
+	a, b, c, _ = lineFunctionAdd(r, minusQ2, unitPoint, r2)
+	addLine(a, b, c)
+
+	return prepared
+}
+
+// MillerPrepared computes the product of Miller(g1[i], g2[i]) in a single
+// loop, which shares the squarings between the pairs. Pairs with the point at
+// infinity are skipped, as PairingCheck does.
+func MillerPrepared(g1 []*G1, g2 []*G2Prepared) *GT {
+	points := make([]*curvePoint, 0, len(g1))
+	lines := make([][]lineCoefficients, 0, len(g1))
+	for i := range g1 {
+		if g1[i].p.IsInfinity() || g2[i].infinity {
+			continue
+		}
+		p := &curvePoint{}
+		p.Set(g1[i].p)
+		p.MakeAffine()
+		points = append(points, p)
+		lines = append(lines, g2[i].lines)
+	}
+
+	ret := (&gfP12{}).SetOne()
+	b, c := &gfP2{}, &gfP2{}
+	step := 0
+	mulLines := func() {
+		for j, p := range points {
+			l := &lines[j][step]
+			b.MulScalar(&l.b, &p.x)
+			c.MulScalar(&l.c, &p.y)
+			mulLine(ret, &l.a, b, c)
+		}
+		step++
+	}
+
+	for i := len(sixuPlus2NAF) - 1; i > 0; i-- {
+		if i != len(sixuPlus2NAF)-1 {
+			ret.Square(ret)
+		}
+		mulLines()
+		if sixuPlus2NAF[i-1] != 0 {
+			mulLines()
+		}
+	}
+	// Q1 and -Q2
+	mulLines()
+	mulLines()
+
+	return &GT{ret}
+}
+
+// IsOne returns whether e is the identity of GT.
+func (e *GT) IsOne() bool {
+	return e.p.IsOne()
+}
@@ -0,0 +1,66 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestMillerPrepared(t *testing.T) {
+	g1 := make([]*G1, 4)
+	g2 := make([]*G2, len(g1))
+	prepared := make([]*G2Prepared, len(g1))
+	expected := new(GT)
+	for i := range g1 {
+		_, g1[i], _ = RandomG1(rand.Reader)
+		_, g2[i], _ = RandomG2(rand.Reader)
+		prepared[i] = g2[i].Prepare()
+		if !bytes.Equal(MillerPrepared(g1[i:i+1], prepared[i:i+1]).Marshal(), Miller(g1[i], g2[i]).Marshal()) {
+			t.Fatalf("pair %d: prepared Miller loop differs", i)
+		}
+		if i == 0 {
+			expected.Set(Miller(g1[i], g2[i]))
+		} else {
+			expected.Add(expected, Miller(g1[i], g2[i]))
+		}
+	}
+	if !bytes.Equal(MillerPrepared(g1, prepared).Marshal(), expected.Marshal()) {
+		t.Fatal("product of prepared Miller loops differs")
+	}
+
+	// e(a, b) * e(-a, b) == 1, pairs with the point at infinity are skipped
+	zero := big.NewInt(0)
+	a := []*G1{g1[0], new(G1).Neg(g1[0]), new(G1).ScalarBaseMult(zero), g1[1]}
+	b := []*G2Prepared{prepared[0], prepared[0], prepared[1], new(G2).ScalarBaseMult(zero).Prepare()}
+	if !MillerPrepared(a, b).Finalize().IsOne() {
+		t.Fatal("pairing check fails")
+	}
+	if MillerPrepared(a[1:], b[1:]).Finalize().IsOne() {
+		t.Fatal("pairing check passes without a pair")
+	}
+}
+
+func BenchmarkMillerPrepared(b *testing.B) {
+	g1 := make([]*G1, 7)
+	g2 := make([]*G2, len(g1))
+	prepared := make([]*G2Prepared, len(g1))
+	for i := range g1 {
+		_, g1[i], _ = RandomG1(rand.Reader)
+		_, g2[i], _ = RandomG2(rand.Reader)
+		prepared[i] = g2[i].Prepare()
+	}
+	b.Run("Miller", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			product := Miller(g1[0], g2[0])
+			for i := 1; i < len(g1); i++ {
+				product.Add(product, Miller(g1[i], g2[i]))
+			}
+		}
+	})
+	b.Run("Prepared", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			MillerPrepared(g1, prepared)
+		}
+	})
+}
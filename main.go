@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"net/http"
 	"os"
@@ -9,17 +10,71 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/shamatar/go-snarks/battleships"
 	handers "github.com/shamatar/go-snarks/server"
 )
 
 func main() {
 	var wait time.Duration = 15
+	proverConfig := flag.String("prover", "", "JSON prover configuration, the libsnark battleship binary is used by default")
+	jobsDir := flag.String("jobs", "", "directory to keep proving jobs in, they are kept in memory by default")
+	workers := flag.Int("workers", 0, "number of concurrent proving jobs, one per CPU by default")
+	rulesFile := flag.String("rules", "", "JSON or YAML ruleset, the classic 10x10 game by default")
+	proveTimeout := flag.Duration("prove-timeout", 10*time.Minute, "time limit of a proving job")
+	jobRetention := flag.Duration("job-retention", 24*time.Hour, "time finished jobs are kept for, zero keeps them forever")
+	flag.Parse()
+
+	var err error
+	rules := battleships.ClassicRuleset()
+	if *rulesFile != "" {
+		rules, err = battleships.LoadRuleset(*rulesFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		handers.SetRuleset(rules)
+	}
+	var config handers.ProverConfig
+	if *proverConfig != "" {
+		config, err = handers.LoadProverConfig(*proverConfig)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	prover, err := handers.NewProver(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// a ruleset the prover can not prove would fail every job
+	if err := handers.CheckCircuit(prover, rules.Circuit); err != nil {
+		log.Fatal(err)
+	}
+	// /verify checks proofs of the same stack and circuit
+	proofVerifier, err := handers.NewVerifier(config, rules.Circuit)
+	if err != nil {
+		log.Fatal(err)
+	}
+	handers.SetVerifier(proofVerifier)
+	var store handers.JobStore = handers.NewMemoryJobStore()
+	if *jobsDir != "" {
+		store, err = handers.NewFileJobStore(*jobsDir)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	// proving outlives requests, clients poll /jobs/{id} for the proof
+	jobs, err := handers.NewJobQueue(prover, store, *workers, *proveTimeout, *jobRetention)
+	if err != nil {
+		log.Fatal(err)
+	}
+	handers.SetJobQueue(jobs)
 
 	r := mux.NewRouter()
 
 	// r.PathPrefix("/static/").Handler(http.StripPrefix("/static/", http.FileServer(http.Dir("static"))))
 	r.HandleFunc("/prove", handers.ProveHander)
 	r.HandleFunc("/verify", handers.VerifyHander)
+	r.HandleFunc("/rules", handers.RulesHander)
+	r.HandleFunc("/jobs/{id}", handers.JobHander).Methods("GET", "DELETE")
 	r.PathPrefix("/public/").Handler(http.StripPrefix("/public/", http.FileServer(http.Dir("./public/"))))
 	// Add your routes as needed
 
@@ -53,6 +108,7 @@ func main() {
 	// Doesn't block if no connections, but will otherwise wait
 	// until the timeout deadline.
 	srv.Shutdown(ctx)
+	jobs.Close()
 	// Optionally, you could run srv.Shutdown in a goroutine and block on
 	// <-ctx.Done() if your application should wait for other services
 	// to finalize based on context cancellation.
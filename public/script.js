@@ -1,28 +1,95 @@
 const grid = document.querySelector("#fieldGame"); // поле для игры
-const letterArray = ['a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j'];
 let selectedShip = { // выбранный тип корабля
     'line': '',
     'size': ''
 };
 let ship = [];	// выделенный на поле корабль
+let rules = { rows: 0, cols: 0, fleet: {} };	// rules of the game served by the backend
 
-// game field
-const drawGrid = () => {
-    for (let i = 0; i < 100; i++) {
-        const cell = document.createElement('div');
-        const char = letterArray[i % 10];
-        const num = Math.trunc((i) / 10) + 1;
+// rows are labeled with letters like on a paper board
+const rowLabel = (row) => row < 26 ? String.fromCharCode(97 + row) : String(row + 1);
+const cellId = (row, col) => `cell-${row}-${col}`;
+// cells and gaps are 20px and 1px wide
+const gridSize = (cells) => `${cells * 21 - 1}px`;
+const repeatFr = (count) => Array(count).fill('1fr').join(' ');
 
-        cell.classList.add("cell");
-        cell.setAttribute("id", char + num);
-        cell.setAttribute("onmouseover", "cellOnmouseOver(event)");
-        cell.setAttribute("onmouseout", "cellOnmouseOut()");
-        cell.setAttribute("onclick", "cellOnclick()");
+const addHeadCells = (head, labels) => {
+    labels.forEach(label => {
+        const cell = document.createElement('div');
+        cell.classList.add("gridHeadCell");
+        cell.textContent = label;
+        head.appendChild(cell);
+    });
+};
 
-        grid.appendChild(cell);
+// game field
+const drawGrid = () => {
+    const head = document.querySelector("#gridHead");
+    head.style.gridTemplateColumns = repeatFr(rules.cols);
+    head.style.width = gridSize(rules.cols);
+    addHeadCells(head, Array.from({ length: rules.cols }, (_, col) => String(col + 1)));
+
+    const headColumn = document.querySelector("#gridHeadColumn");
+    headColumn.style.gridTemplateRows = repeatFr(rules.rows);
+    headColumn.style.height = gridSize(rules.rows);
+    addHeadCells(headColumn, Array.from({ length: rules.rows }, (_, row) => rowLabel(row)));
+
+    grid.style.gridTemplateRows = repeatFr(rules.rows);
+    grid.style.gridTemplateColumns = repeatFr(rules.cols);
+    grid.style.width = gridSize(rules.cols);
+    grid.style.height = gridSize(rules.rows);
+    // the grid is filled column by column
+    for (let col = 0; col < rules.cols; col++) {
+        for (let row = 0; row < rules.rows; row++) {
+            const cell = document.createElement('div');
+
+            cell.classList.add("cell");
+            cell.setAttribute("id", cellId(row, col));
+            cell.dataset.row = row;
+            cell.dataset.col = col;
+            cell.setAttribute("onmouseover", "cellOnmouseOver(event)");
+            cell.setAttribute("onmouseout", "cellOnmouseOut()");
+            cell.setAttribute("onclick", "cellOnclick()");
+
+            grid.appendChild(cell);
+        }
     }
 };
-drawGrid();
+
+// ships of every length of the fleet, placed vertically or horizontally
+const drawShips = () => {
+    const ships = document.querySelector("#ships");
+    const lengths = Object.keys(rules.fleet)
+        .map(Number)
+        .filter(length => rules.fleet[length] > 0)
+        .sort((a, b) => b - a);
+    lengths.forEach(length => {
+        const lines = length === 1 ? ['vertically'] : ['vertically', 'horizontally'];
+        lines.forEach(line => {
+            const element = document.createElement('div');
+            element.classList.add("ship");
+            element.dataset.line = line;
+            element.dataset.size = length;
+            element.title = `${rules.fleet[length]} x ${length}`;
+            if (line === 'vertically') {
+                element.style.gridTemplateRows = repeatFr(length);
+                element.style.gridTemplateColumns = '1fr';
+                element.style.width = gridSize(1);
+            } else {
+                element.style.gridTemplateRows = '1fr';
+                element.style.gridTemplateColumns = repeatFr(length);
+                element.style.width = gridSize(length);
+            }
+            element.setAttribute("onclick", "choiceShip(this)");
+            for (let i = 0; i < length; i++) {
+                const cell = document.createElement('div');
+                cell.classList.add("shipCell");
+                element.appendChild(cell);
+            }
+            ships.appendChild(element);
+        });
+    });
+};
 
 const getAllCells = () => {
     const cellsList = document.querySelectorAll(".cell");
@@ -41,33 +108,17 @@ const choiceShip = (element) => {
 
 // наведение курсора на ячейку поля
 const cellOnmouseOver = (e) => {
-    const thisCell = e.target.getAttribute('id');  // клетка под курсором
-    const coordinates = {  // координаты клетки под курсором
-        'char': thisCell.charAt(0),
-        'num': Number(thisCell.substr(1))
-    };
-    let allCells = getAllCells();
+    const row = Number(e.target.dataset.row);  // клетка под курсором
+    const col = Number(e.target.dataset.col);
     let thisShipCills = [];  // массив клеток "под кораблем"
 
-    if (selectedShip.line === 'vertically') {
-        allCells.map(cell => {
-            const cellChar = letterArray.indexOf(cell.charAt(0));
-            if (Number(cell.substr(1)) === coordinates.num) {
-                if (letterArray.indexOf(coordinates.char) - selectedShip.size < cellChar
-                    && cellChar <= letterArray.indexOf(coordinates.char)) {
-                    thisShipCills.push(cell);
-                }
-            }
-        });
-    } else if (selectedShip.line === 'horizontally') {
-        allCells.map(cell => {
-            const cellNum = Number(cell.substr(1));
-            if (cell.charAt(0) === coordinates.char) {
-                if (coordinates.num - selectedShip.size < cellNum && cellNum <= coordinates.num) {
-                    thisShipCills.push(cell);
-                }
-            }
-        });
+    // the ship ends at the cell under the cursor
+    for (let i = 0; i < selectedShip.size; i++) {
+        if (selectedShip.line === 'vertically' && row - i >= 0) {
+            thisShipCills.push(cellId(row - i, col));
+        } else if (selectedShip.line === 'horizontally' && col - i >= 0) {
+            thisShipCills.push(cellId(row, col - i));
+        }
     }
     thisShipCills.map(cell => {
         document.querySelector('#' + cell).classList.add("cellHover");
@@ -95,13 +146,11 @@ const cellOnclick = () => {
 
 // get layout of the ships as a 2D array
 const getLayout = () => {
-    let num = 10;
-    let char = letterArray;
     let card = [];
-    for (let i = 0; i < char.length; i++) {
+    for (let i = 0; i < rules.rows; i++) {
         card[i] = [];
-        for (let j = 0; j < num; j++) {
-            const a = document.querySelector(`#${char[i]}${j + 1}`);
+        for (let j = 0; j < rules.cols; j++) {
+            const a = document.querySelector('#' + cellId(i, j));
             card[i][j] = a.classList.contains('cell2') ? 1 : 0;
         }
     }
@@ -119,12 +168,38 @@ const sendJSON = async (obj) => {
         },
         body: JSON.stringify(obj)
     });
-    const content = await rawResponse.json();
-    console.log(content);
-    return content;
+    let job = await rawResponse.json();
+    // proving takes a while, so the server returns a job to poll
+    while (job.status === 'queued' || job.status === 'running') {
+        await new Promise(resolve => setTimeout(resolve, 1000));
+        const jobResponse = await fetch(`/jobs/${job.id}`, {
+            headers: {
+                'Accept': 'application/json'
+            }
+        });
+        job = await jobResponse.json();
+    }
+    console.log(job);
+    if (job.status !== 'done') {
+        return { error: true, reason: job.error || job.reason || job.status };
+    }
+    return { proof: job.proof, hash: "" };
+};
+
+// the board and the fleet are laid out by the rules of the backend
+const loadRules = async () => {
+    const rawResponse = await fetch('/rules', {
+        headers: {
+            'Accept': 'application/json'
+        }
+    });
+    rules = await rawResponse.json();
+    drawGrid();
+    drawShips();
 };
 
 window.onload = () => {
+    loadRules();
     document.getElementById("sendLayoutBtn").addEventListener("click", sendLayout);
     document.getElementById("verifyBtn").addEventListener("click", sendVerify);
 };
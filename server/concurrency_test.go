@@ -0,0 +1,270 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shamatar/go-snarks/battleships"
+)
+
+// fakeProver writes its arguments as the proof through a witness file. It
+// fails if the workspace already has a proof or misses the key it reads
+const fakeProver = `#!/bin/sh
+[ "$1" = "-p" ] || exit 1
+cat key.txt > /dev/null || exit 1
+[ -e proof.txt ] && exit 1
+printf '%s' "$2" > witness.txt
+sleep 0.01
+printf '%s %s' "$(cat witness.txt)" "$3" > proof.txt
+`
+
+// installFakeProver starts a job queue with a libsnark prover running the
+// fake binary in temporary directories
+func installFakeProver(t *testing.T) (*LibsnarkProver, func()) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake prover is a shell script")
+	}
+	proverDir, err := ioutil.TempDir("", "prover")
+	if err != nil {
+		t.Fatal(err)
+	}
+	workspaceRoot, err := ioutil.TempDir("", "workspaces")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"battleship":      fakeProver,
+		"key.txt":         "key",
+		"witness.txt":     "stale witness",
+		libsnarkProofFile: "stale proof",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(proverDir, name), []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p := &LibsnarkProver{Dir: proverDir, Binary: "battleship", ReadOnly: []string{"key.txt"}, WorkspaceRoot: workspaceRoot}
+	stop := startJobs(p)
+	return p, func() {
+		stop()
+		os.RemoveAll(proverDir)
+		os.RemoveAll(workspaceRoot)
+	}
+}
+
+// shipRows are rows of a valid board with the widths of their ships
+var shipRows = []struct {
+	row   string
+	width int
+}{
+	{"1111", 4},
+	{"1110111", 7},
+	{"11011011", 8},
+	{"1010101", 7},
+}
+
+// board returns a distinct valid board for every player below 336,
+// ship rows are shifted by digits of the player number
+func board(player int) ([][]int, string) {
+	arr := make([][]int, 10)
+	for i := range arr {
+		arr[i] = make([]int, 10)
+	}
+	for i, ships := range shipRows {
+		shifts := 11 - ships.width
+		shift := player % shifts
+		player /= shifts
+		for j, c := range ships.row {
+			arr[2*i][shift+j] = int(c - '0')
+		}
+	}
+	var expected strings.Builder
+	expected.WriteString("b")
+	for i := range arr {
+		for j := range arr[i] {
+			fmt.Fprint(&expected, arr[i][j])
+		}
+	}
+	return arr, expected.String()
+}
+
+// startJobs sets a job queue with the prover and returns a function closing it
+func startJobs(p Prover) func() {
+	// an empty store has no jobs to fail
+	q, _ := NewJobQueue(p, NewMemoryJobStore(), 0, time.Minute, 0)
+	SetJobQueue(q)
+	return func() {
+		q.Close()
+		SetJobQueue(nil)
+	}
+}
+
+// getJob calls JobHander like a client polling the job
+func getJob(method, id string) (*Job, int, error) {
+	rec := httptest.NewRecorder()
+	JobHander(rec, httptest.NewRequest(method, "/jobs/"+id, nil))
+	job := new(Job)
+	err := json.Unmarshal(rec.Body.Bytes(), job)
+	return job, rec.Code, err
+}
+
+// prove submits the board and polls the job until it is finished
+func prove(arr [][]int) (*Job, error) {
+	body, _ := json.Marshal(arr)
+	rec := httptest.NewRecorder()
+	ProveHander(rec, httptest.NewRequest("POST", "/prove", bytes.NewReader(body)))
+	job := new(Job)
+	if err := json.Unmarshal(rec.Body.Bytes(), job); err != nil {
+		return nil, err
+	}
+	if rec.Code != http.StatusAccepted || job.Status != JobQueued {
+		return nil, fmt.Errorf("job is not queued: %d %s", rec.Code, rec.Body)
+	}
+	for !job.finished() {
+		time.Sleep(10 * time.Millisecond)
+		var err error
+		if job, _, err = getJob("GET", job.ID); err != nil {
+			return nil, err
+		}
+	}
+	if job.Status != JobDone {
+		return nil, fmt.Errorf("job is %s: %s", job.Status, job.Error)
+	}
+	return job, nil
+}
+
+func TestProveHanderWorkspace(t *testing.T) {
+	p, cleanup := installFakeProver(t)
+	defer cleanup()
+	arr, expected := board(5)
+	resp, err := prove(arr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := strings.Fields(resp.Proof)
+	if len(fields) != 2 || fields[0] != expected {
+		t.Fatalf("unexpected proof %q", resp.Proof)
+	}
+	for name, content := range map[string]string{libsnarkProofFile: "stale proof", "witness.txt": "stale witness"} {
+		stale, err := ioutil.ReadFile(filepath.Join(p.Dir, name))
+		if err != nil || string(stale) != content {
+			t.Fatalf("%s in the prover directory is touched: %q, %v", name, stale, err)
+		}
+	}
+	left, err := ioutil.ReadDir(p.WorkspaceRoot)
+	if err != nil || len(left) != 0 {
+		t.Fatalf("%d workspaces are left: %v", len(left), err)
+	}
+}
+
+func TestProveHanderInvalidBoard(t *testing.T) {
+	fake := &FakeProver{Err: errors.New("Invalid board is proved")}
+	defer startJobs(fake)()
+	arr, _ := board(0)
+	arr[1][0] = 1
+	body, _ := json.Marshal(arr)
+	rec := httptest.NewRecorder()
+	ProveHander(rec, httptest.NewRequest("POST", "/prove", bytes.NewReader(body)))
+	var resp proverResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusBadRequest || !resp.Error || len(resp.Violations) == 0 ||
+		resp.Violations[0].Code != battleships.ShipsTouch {
+		t.Fatalf("invalid board is not rejected properly: %d %+v", rec.Code, resp)
+	}
+}
+
+func TestConcurrentHanders(t *testing.T) {
+	if err := LoadVerifyingKey("../vk_key.txt"); err != nil {
+		t.Fatal(err)
+	}
+	proof, err := ioutil.ReadFile("../proof.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(proof), "\n")
+	lines[3] = lines[4]
+	corrupted := strings.Join(lines, "\n")
+	p, cleanup := installFakeProver(t)
+	defer cleanup()
+
+	const provers, verifiers = 200, 100
+	var wg sync.WaitGroup
+	errs := make(chan error, provers+verifiers)
+	salts := make([]string, provers)
+	for i := 0; i < provers; i++ {
+		wg.Add(1)
+		go func(player int) {
+			defer wg.Done()
+			arr, expected := board(player)
+			resp, err := prove(arr)
+			if err != nil {
+				errs <- err
+				return
+			}
+			fields := strings.Fields(resp.Proof)
+			if len(fields) != 2 || fields[0] != expected {
+				errs <- fmt.Errorf("player %d got proof %q", player, resp.Proof)
+				return
+			}
+			salts[player] = fields[1]
+		}(i)
+	}
+	for i := 0; i < verifiers; i++ {
+		wg.Add(1)
+		go func(valid bool) {
+			defer wg.Done()
+			body, _ := json.Marshal(verificationRequest{Proof: corrupted})
+			if valid {
+				body, _ = json.Marshal(verificationRequest{Proof: string(proof)})
+			}
+			rec := httptest.NewRecorder()
+			VerifyHander(rec, httptest.NewRequest("POST", "/verify", bytes.NewReader(body)))
+			var resp verificationResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				errs <- err
+				return
+			}
+			if resp.Valid != valid || resp.Error == valid {
+				errs <- fmt.Errorf("proof valid %v got %+v", valid, resp)
+			}
+		}(i%2 == 0)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if t.Failed() {
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, salt := range salts {
+		if seen[salt] {
+			t.Fatalf("salt %s is reused", salt)
+		}
+		seen[salt] = true
+	}
+	stale, err := ioutil.ReadFile(filepath.Join(p.Dir, "witness.txt"))
+	if err != nil || string(stale) != "stale witness" {
+		t.Fatalf("witness in the prover directory is touched: %q, %v", stale, err)
+	}
+	left, err := ioutil.ReadDir(p.WorkspaceRoot)
+	if err != nil || len(left) != 0 {
+		t.Fatalf("%d workspaces are left: %v", len(left), err)
+	}
+}
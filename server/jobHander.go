@@ -0,0 +1,74 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path"
+)
+
+// jobs runs proofs submitted by ProveHander, it is set by SetJobQueue
+var jobs *JobQueue
+
+// SetJobQueue sets the queue used by ProveHander and JobHander
+func SetJobQueue(q *JobQueue) {
+	jobs = q
+}
+
+// JobHander reports the job on GET and cancels it on DELETE,
+// the job id is the last element of the path
+func JobHander(w http.ResponseWriter, r *http.Request) {
+	if jobs == nil {
+		writeJobError(w, http.StatusServiceUnavailable, ErrQueueClosed)
+		return
+	}
+	id := path.Base(r.URL.Path)
+	var job *Job
+	var err error
+	switch r.Method {
+	case http.MethodGet:
+		job, err = jobs.Get(id)
+	case http.MethodDelete:
+		err = jobs.Cancel(id)
+		if err == nil {
+			job, err = jobs.Get(id)
+		}
+	default:
+		writeJobError(w, http.StatusMethodNotAllowed, errors.New("Method not allowed"))
+		return
+	}
+	switch err {
+	case nil:
+		writeJob(w, http.StatusOK, job)
+	case ErrJobNotFound:
+		writeJobError(w, http.StatusNotFound, err)
+	case ErrJobFinished:
+		writeJobError(w, http.StatusConflict, err)
+	default:
+		writeJobError(w, http.StatusInternalServerError, err)
+	}
+}
+
+func writeJob(w http.ResponseWriter, status int, job *Job) {
+	js, err := json.Marshal(job)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(js)
+}
+
+func writeJobError(w http.ResponseWriter, status int, reason error) {
+	js, err := json.Marshal(proverResponse{Error: true, Reason: reason.Error()})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(js)
+}
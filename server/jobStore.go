@@ -0,0 +1,179 @@
+package server
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// JobStore keeps states of jobs, returned jobs are copies
+type JobStore interface {
+	Put(job *Job) error
+	// Get and Delete return ErrJobNotFound for unknown jobs
+	Get(id string) (*Job, error)
+	Delete(id string) error
+	List() ([]*Job, error)
+}
+
+// MemoryJobStore keeps jobs until they are deleted or the server stops
+type MemoryJobStore struct {
+	mu   sync.RWMutex
+	jobs map[string]Job
+}
+
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{jobs: make(map[string]Job)}
+}
+
+func (s *MemoryJobStore) Put(job *Job) error {
+	copied := *job
+	copied.Inputs = append([]string(nil), job.Inputs...)
+	s.mu.Lock()
+	s.jobs[job.ID] = copied
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryJobStore) Get(id string) (*Job, error) {
+	s.mu.RLock()
+	job, ok := s.jobs[id]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	job.Inputs = append([]string(nil), job.Inputs...)
+	return &job, nil
+}
+
+func (s *MemoryJobStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[id]; !ok {
+		return ErrJobNotFound
+	}
+	delete(s.jobs, id)
+	return nil
+}
+
+func (s *MemoryJobStore) List() ([]*Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	jobs := make([]*Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		copied := job
+		copied.Inputs = append([]string(nil), job.Inputs...)
+		jobs = append(jobs, &copied)
+	}
+	return jobs, nil
+}
+
+// FileJobStore keeps every job as a JSON file named by its id in Dir,
+// so results survive restarts
+type FileJobStore struct {
+	Dir string
+}
+
+func NewFileJobStore(dir string) (*FileJobStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileJobStore{Dir: dir}, nil
+}
+
+// path returns the file of the job, ids are hex so they never escape Dir
+func (s *FileJobStore) path(id string) (string, error) {
+	if _, err := hex.DecodeString(id); err != nil || id == "" {
+		return "", ErrJobNotFound
+	}
+	return filepath.Join(s.Dir, id+".json"), nil
+}
+
+func (s *FileJobStore) Put(job *Job) error {
+	path, err := s.path(job.ID)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	// readers never see a partially written file
+	tmp, err := ioutil.TempFile(s.Dir, job.ID)
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+func (s *FileJobStore) Get(id string) (*Job, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	job := new(Job)
+	if err := json.Unmarshal(content, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *FileJobStore) Delete(id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		return ErrJobNotFound
+	}
+	return err
+}
+
+// List skips temporary files of unfinished writes, unreadable job files are
+// logged and skipped, so that one broken file does not hide the other jobs
+func (s *FileJobStore) List() ([]*Job, error) {
+	files, err := ioutil.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var jobs []*Job
+	for _, file := range files {
+		id := strings.TrimSuffix(file.Name(), ".json")
+		if id == file.Name() {
+			continue
+		}
+		job, err := s.Get(id)
+		if err == ErrJobNotFound {
+			// deleted meanwhile or not a job file
+			continue
+		}
+		if err != nil {
+			log.Printf("Can not read job %s: %v", id, err)
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
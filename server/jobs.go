@@ -0,0 +1,348 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// JobStatus is the state of a proving job
+type JobStatus string
+
+const (
+	JobQueued   JobStatus = "queued"
+	JobRunning  JobStatus = "running"
+	JobDone     JobStatus = "done"
+	JobFailed   JobStatus = "failed"
+	JobCanceled JobStatus = "canceled"
+)
+
+// maxQueuedJobs bounds the number of jobs waiting for a worker
+const maxQueuedJobs = 1024
+
+var (
+	ErrJobNotFound = errors.New("Job not found")
+	ErrJobFinished = errors.New("Job is already finished")
+	ErrQueueFull   = errors.New("Too many queued jobs")
+	ErrQueueClosed = errors.New("Job queue is closed")
+	ErrJobTimeout  = errors.New("Proving has timed out")
+	// ErrJobInterrupted is recorded for jobs left unfinished by a previous run of the server
+	ErrJobInterrupted = errors.New("Server has stopped before the job has finished")
+)
+
+// Job is a proving request, private inputs are never stored with it
+type Job struct {
+	ID      string    `json:"id"`
+	Circuit string    `json:"circuit"`
+	Status  JobStatus `json:"status"`
+	Proof   string    `json:"proof,omitempty"`
+	// Inputs are public inputs of the proof in decimal
+	Inputs  []string  `json:"inputs,omitempty"`
+	Error   string    `json:"error,omitempty"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// finished tells if the job will not change anymore
+func (j *Job) finished() bool {
+	return j.Status == JobDone || j.Status == JobFailed || j.Status == JobCanceled
+}
+
+// newJobID returns a random hex identifier
+func newJobID() (string, error) {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id[:]), nil
+}
+
+// pendingJob carries private inputs from Submit to a worker
+type pendingJob struct {
+	id        string
+	circuitID string
+	inputs    PrivateInputs
+}
+
+// activeJob is a queued or running job that can be canceled
+type activeJob struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	running bool
+}
+
+// JobQueue runs proving jobs on a bounded pool of workers and records
+// their progress in a store
+type JobQueue struct {
+	prover    Prover
+	store     JobStore
+	timeout   time.Duration
+	retention time.Duration
+	pending   chan pendingJob
+	// done stops pruning once the queue is closed
+	done chan struct{}
+	wg   sync.WaitGroup
+
+	// mu guards active and closed and orders status updates of a job
+	mu     sync.Mutex
+	active map[string]*activeJob
+	closed bool
+}
+
+// NewJobQueue starts workers, a non-positive number means one per CPU.
+// Every job is given timeout to finish, zero means no timeout. Finished jobs
+// are deleted from the store after retention, zero keeps them forever.
+// Private inputs are not stored, so unfinished jobs of the store can not be
+// resumed and are marked as failed
+func NewJobQueue(prover Prover, store JobStore, workers int, timeout, retention time.Duration) (*JobQueue, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if err := failUnfinished(store); err != nil {
+		return nil, err
+	}
+	q := &JobQueue{
+		prover:    prover,
+		store:     store,
+		timeout:   timeout,
+		retention: retention,
+		pending:   make(chan pendingJob, maxQueuedJobs),
+		done:      make(chan struct{}),
+		active:    make(map[string]*activeJob),
+	}
+	for i := 0; i < workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+	if retention > 0 {
+		q.wg.Add(1)
+		go q.prune()
+	}
+	return q, nil
+}
+
+// failUnfinished marks queued and running jobs of the store as failed
+func failUnfinished(store JobStore) error {
+	jobs, err := store.List()
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if job.finished() {
+			continue
+		}
+		job.Status = JobFailed
+		job.Error = ErrJobInterrupted.Error()
+		job.Updated = time.Now().UTC()
+		if err := store.Put(job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Submit queues a job and returns it in the queued state
+func (q *JobQueue) Submit(circuitID string, inputs PrivateInputs) (*Job, error) {
+	id, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	job := &Job{ID: id, Circuit: circuitID, Status: JobQueued, Created: now, Updated: now}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return nil, ErrQueueClosed
+	}
+	if len(q.pending) == cap(q.pending) {
+		return nil, ErrQueueFull
+	}
+	if err := q.store.Put(job); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	q.active[id] = &activeJob{ctx: ctx, cancel: cancel}
+	// never blocks, there is room and only Submit sends under the lock
+	q.pending <- pendingJob{id, circuitID, inputs.copy()}
+	return job, nil
+}
+
+// Get returns the current state of the job
+func (q *JobQueue) Get(id string) (*Job, error) {
+	return q.store.Get(id)
+}
+
+// Cancel stops a queued or running job
+func (q *JobQueue) Cancel(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	active, ok := q.active[id]
+	if !ok {
+		if _, err := q.store.Get(id); err != nil {
+			return err
+		}
+		return ErrJobFinished
+	}
+	active.cancel()
+	if active.running {
+		// the worker records the cancellation when the prover returns
+		return nil
+	}
+	delete(q.active, id)
+	return q.update(id, func(job *Job) { job.Status = JobCanceled })
+}
+
+// Close cancels all jobs and waits for workers to exit
+func (q *JobQueue) Close() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	for _, active := range q.active {
+		active.cancel()
+	}
+	close(q.pending)
+	close(q.done)
+	q.mu.Unlock()
+	q.wg.Wait()
+}
+
+// update changes the stored job, it is called with mu held
+func (q *JobQueue) update(id string, change func(job *Job)) error {
+	job, err := q.store.Get(id)
+	if err != nil {
+		return err
+	}
+	change(job)
+	job.Updated = time.Now().UTC()
+	return q.store.Put(job)
+}
+
+// prune deletes expired jobs every tenth of the retention until the queue is closed
+func (q *JobQueue) prune() {
+	defer q.wg.Done()
+	interval := q.retention / 10
+	if interval == 0 {
+		interval = q.retention
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-q.done:
+			return
+		case now := <-ticker.C:
+			q.pruneFinished(now)
+		}
+	}
+}
+
+// pruneFinished deletes jobs finished more than retention before now.
+// Finished jobs never change, so they are deleted without the lock
+func (q *JobQueue) pruneFinished(now time.Time) {
+	jobs, err := q.store.List()
+	if err != nil {
+		log.Printf("Can not list jobs to prune: %v", err)
+		return
+	}
+	for _, job := range jobs {
+		if !job.finished() || now.Sub(job.Updated) < q.retention {
+			continue
+		}
+		if err := q.store.Delete(job.ID); err != nil && err != ErrJobNotFound {
+			log.Printf("Can not delete job %s: %v", job.ID, err)
+		}
+	}
+}
+
+func (q *JobQueue) work() {
+	defer q.wg.Done()
+	for pending := range q.pending {
+		q.run(pending)
+	}
+}
+
+func (q *JobQueue) run(pending pendingJob) {
+	q.mu.Lock()
+	active, ok := q.active[pending.id]
+	if !ok {
+		// canceled while queued
+		q.mu.Unlock()
+		return
+	}
+	if active.ctx.Err() != nil {
+		// the queue is closed
+		delete(q.active, pending.id)
+		if err := q.update(pending.id, func(job *Job) { job.Status = JobCanceled }); err != nil {
+			q.fail(pending.id, err)
+		}
+		q.mu.Unlock()
+		return
+	}
+	if err := q.update(pending.id, func(job *Job) { job.Status = JobRunning }); err != nil {
+		// the proof could not be stored either, so it is not computed
+		delete(q.active, pending.id)
+		active.cancel()
+		q.fail(pending.id, err)
+		q.mu.Unlock()
+		return
+	}
+	active.running = true
+	q.mu.Unlock()
+
+	ctx := active.ctx
+	if q.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, q.timeout)
+		defer cancel()
+	}
+	result, err := q.prover.Prove(ctx, pending.circuitID, pending.inputs)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	defer active.cancel()
+	delete(q.active, pending.id)
+	err = q.update(pending.id, func(job *Job) {
+		switch {
+		case active.ctx.Err() != nil:
+			job.Status = JobCanceled
+		case ctx.Err() == context.DeadlineExceeded:
+			job.Status = JobFailed
+			job.Error = ErrJobTimeout.Error()
+		case err != nil:
+			job.Status = JobFailed
+			job.Error = err.Error()
+		default:
+			job.Status = JobDone
+			job.Proof = result.Proof
+			job.Inputs = make([]string, len(result.Inputs))
+			for i, input := range result.Inputs {
+				job.Inputs[i] = input.String()
+			}
+		}
+	})
+	if err != nil {
+		q.fail(pending.id, err)
+	}
+}
+
+// fail records the job as failed after its update has failed with cause,
+// workers have nobody else to report the error to. It is called with mu held
+func (q *JobQueue) fail(id string, cause error) {
+	log.Printf("Can not update job %s: %v", id, cause)
+	err := q.update(id, func(job *Job) {
+		job.Status = JobFailed
+		job.Error = cause.Error()
+	})
+	if err != nil {
+		log.Printf("Can not record failure of job %s: %v", id, err)
+	}
+}
@@ -0,0 +1,279 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shamatar/go-snarks/verifier"
+)
+
+// blockingProver runs until the job is canceled or timed out
+type blockingProver struct {
+	started chan int64
+}
+
+func (p *blockingProver) Prove(ctx context.Context, circuitID string, inputs PrivateInputs) (*ProofResult, error) {
+	p.started <- inputs.Salt.Int64()
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// salted returns inputs told apart by the salt
+func salted(salt int64) PrivateInputs {
+	return PrivateInputs{Salt: big.NewInt(salt)}
+}
+
+// failingStore fails to store jobs with the status
+type failingStore struct {
+	JobStore
+	status JobStatus
+}
+
+func (s *failingStore) Put(job *Job) error {
+	if job.Status == s.status {
+		return errors.New("Disk is full")
+	}
+	return s.JobStore.Put(job)
+}
+
+// waitJob polls the queue until the job is finished
+func waitJob(t *testing.T, q *JobQueue, id string) *Job {
+	for {
+		job, err := q.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.finished() {
+			return job
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestJobStores(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fileStore, err := NewFileJobStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, store := range []JobStore{NewMemoryJobStore(), fileStore} {
+		job := &Job{ID: "00ff", Status: JobDone, Proof: "proof", Inputs: []string{"1"}, Created: time.Now().UTC()}
+		if err := store.Put(job); err != nil {
+			t.Fatal(err)
+		}
+		job.Inputs[0] = "2"
+		stored, err := store.Get("00ff")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.Proof != "proof" || stored.Inputs[0] != "1" || !stored.Created.Equal(job.Created) {
+			t.Fatalf("%T: unexpected job %+v", store, stored)
+		}
+		for _, id := range []string{"00fe", "", "../jobs", "."} {
+			if _, err := store.Get(id); err != ErrJobNotFound {
+				t.Fatalf("%T: %q: expected %v, got %v", store, id, ErrJobNotFound, err)
+			}
+		}
+		store.Put(&Job{ID: "00aa", Status: JobQueued})
+		if jobs, err := store.List(); err != nil || len(jobs) != 2 {
+			t.Fatalf("%T: unexpected jobs %v, %v", store, jobs, err)
+		}
+		if err := store.Delete("00aa"); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Delete("00aa"); err != ErrJobNotFound {
+			t.Fatalf("%T: expected %v, got %v", store, ErrJobNotFound, err)
+		}
+		if jobs, err := store.List(); err != nil || len(jobs) != 1 || jobs[0].ID != "00ff" {
+			t.Fatalf("%T: unexpected jobs %v, %v", store, jobs, err)
+		}
+	}
+	// files survive the store
+	reopened, _ := NewFileJobStore(dir)
+	if job, err := reopened.Get("00ff"); err != nil || job.Status != JobDone {
+		t.Fatalf("job is not kept: %+v, %v", job, err)
+	}
+}
+
+func TestJobQueue(t *testing.T) {
+	fake := &FakeProver{Proof: "proof", Inputs: verifier.Witness{big.NewInt(7)}}
+	q, err := NewJobQueue(fake, NewMemoryJobStore(), 2, time.Minute, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := q.Submit(BattleshipCircuit, PrivateInputs{Board: [][]int{{1}}, Salt: big.NewInt(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job = waitJob(t, q, job.ID)
+	if job.Status != JobDone || job.Proof != "proof" || len(job.Inputs) != 1 || job.Inputs[0] != "7" {
+		t.Fatalf("unexpected job %+v", job)
+	}
+	if err := q.Cancel(job.ID); err != ErrJobFinished {
+		t.Fatalf("expected %v, got %v", ErrJobFinished, err)
+	}
+	if err := q.Cancel("00"); err != ErrJobNotFound {
+		t.Fatalf("expected %v, got %v", ErrJobNotFound, err)
+	}
+
+	fake.Err = errors.New("Prover has crashed")
+	job, _ = q.Submit(BattleshipCircuit, PrivateInputs{})
+	if job = waitJob(t, q, job.ID); job.Status != JobFailed || job.Error != fake.Err.Error() {
+		t.Fatalf("unexpected job %+v", job)
+	}
+	q.Close()
+	if _, err := q.Submit(BattleshipCircuit, PrivateInputs{}); err != ErrQueueClosed {
+		t.Fatalf("expected %v, got %v", ErrQueueClosed, err)
+	}
+}
+
+func TestUnfinishedJobsFail(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, _ := NewFileJobStore(dir)
+	// jobs left by a server that has stopped
+	store.Put(&Job{ID: "01", Status: JobQueued})
+	store.Put(&Job{ID: "02", Status: JobRunning})
+	store.Put(&Job{ID: "03", Status: JobDone, Proof: "proof"})
+	// a broken file does not stop the server
+	if err := ioutil.WriteFile(filepath.Join(dir, "04.json"), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	q, err := NewJobQueue(&FakeProver{}, store, 1, time.Minute, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+	for _, id := range []string{"01", "02"} {
+		if job, _ := q.Get(id); job.Status != JobFailed || job.Error != ErrJobInterrupted.Error() {
+			t.Fatalf("unexpected job %+v", job)
+		}
+	}
+	if job, _ := q.Get("03"); job.Status != JobDone || job.Proof != "proof" {
+		t.Fatalf("finished job is changed: %+v", job)
+	}
+}
+
+func TestJobUpdateErrors(t *testing.T) {
+	for _, status := range []JobStatus{JobRunning, JobDone} {
+		q, err := NewJobQueue(&FakeProver{Proof: "proof"}, &failingStore{NewMemoryJobStore(), status}, 1, time.Minute, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		job, err := q.Submit(BattleshipCircuit, PrivateInputs{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job = waitJob(t, q, job.ID); job.Status != JobFailed || job.Error != "Disk is full" {
+			t.Fatalf("%s: unexpected job %+v", status, job)
+		}
+		q.Close()
+	}
+}
+
+func TestJobRetention(t *testing.T) {
+	store := NewMemoryJobStore()
+	q, err := NewJobQueue(&FakeProver{Proof: "proof"}, store, 1, time.Minute, 20*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+	// unfinished jobs are kept however old they are
+	store.Put(&Job{ID: "00ff", Status: JobRunning})
+	job, _ := q.Submit(BattleshipCircuit, PrivateInputs{})
+	waitJob(t, q, job.ID)
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(time.Millisecond) {
+		if _, err := q.Get(job.ID); err == ErrJobNotFound {
+			if _, err := q.Get("00ff"); err != nil {
+				t.Fatalf("unfinished job is pruned: %v", err)
+			}
+			return
+		}
+	}
+	t.Fatal("finished job is kept after the retention")
+}
+
+func TestJobCancellation(t *testing.T) {
+	prover := &blockingProver{started: make(chan int64, 4)}
+	q, err := NewJobQueue(prover, NewMemoryJobStore(), 1, 50*time.Millisecond, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	// the first job times out
+	timedOut, _ := q.Submit(BattleshipCircuit, salted(1))
+	if input := <-prover.started; input != 1 {
+		t.Fatalf("unexpected job %d", input)
+	}
+	if job := waitJob(t, q, timedOut.ID); job.Status != JobFailed || job.Error != ErrJobTimeout.Error() {
+		t.Fatalf("unexpected job %+v", job)
+	}
+
+	// the only worker is busy, so the second job waits in the queue
+	running, _ := q.Submit(BattleshipCircuit, salted(2))
+	<-prover.started
+	queued, _ := q.Submit(BattleshipCircuit, salted(3))
+	if err := q.Cancel(queued.ID); err != nil {
+		t.Fatal(err)
+	}
+	if job, _ := q.Get(queued.ID); job.Status != JobCanceled {
+		t.Fatalf("unexpected job %+v", job)
+	}
+	if err := q.Cancel(running.ID); err != nil {
+		t.Fatal(err)
+	}
+	if job := waitJob(t, q, running.ID); job.Status != JobCanceled {
+		t.Fatalf("unexpected job %+v", job)
+	}
+
+	last, _ := q.Submit(BattleshipCircuit, salted(4))
+	// a canceled job is never started
+	if input := <-prover.started; input != 4 {
+		t.Fatalf("unexpected job %d", input)
+	}
+	q.Close()
+	if job, _ := q.Get(last.ID); job.Status != JobCanceled {
+		t.Fatalf("unexpected job %+v", job)
+	}
+}
+
+func TestJobHander(t *testing.T) {
+	prover := &blockingProver{started: make(chan int64, 1)}
+	defer startJobs(prover)()
+	job, err := jobs.Submit(BattleshipCircuit, salted(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-prover.started
+	if job, code, err := getJob("GET", job.ID); err != nil || code != http.StatusOK || job.Status != JobRunning {
+		t.Fatalf("unexpected job %+v, %d, %v", job, code, err)
+	}
+	if job, code, err := getJob("DELETE", job.ID); err != nil || code != http.StatusOK || job.Status == JobDone {
+		t.Fatalf("unexpected job %+v, %d, %v", job, code, err)
+	}
+	waitJob(t, jobs, job.ID)
+	if _, code, _ := getJob("DELETE", job.ID); code != http.StatusConflict {
+		t.Fatalf("finished job is canceled: %d", code)
+	}
+	if _, code, _ := getJob("GET", "0123"); code != http.StatusNotFound {
+		t.Fatalf("unknown job is found: %d", code)
+	}
+	if _, code, _ := getJob("POST", job.ID); code != http.StatusMethodNotAllowed {
+		t.Fatalf("unexpected code %d", code)
+	}
+}
@@ -1,19 +1,20 @@
 package server
 
 import (
+	"crypto/rand"
 	"encoding/json"
-	"io/ioutil"
+	"errors"
 	"log"
-	"fmt"
-	"bytes"
-	"math/rand"
+	"math/big"
 	"net/http"
-	"os/exec"
-	"strconv"
+
+	"github.com/shamatar/go-snarks/battleships"
 )
 
 type proverResponse struct {
-	Error bool `json:"error"`
+	Error      bool                    `json:"error"`
+	Reason     string                  `json:"reason,omitempty"`
+	Violations []battleships.Violation `json:"violations,omitempty"`
 }
 
 type verificationRequest struct {
@@ -47,77 +48,63 @@ func ProveHander(w http.ResponseWriter, r *http.Request) {
 		writeError(w)
 		return
 	}
-	log.Printf("Unmarshaled: %v", arr)
-	if len(arr) != 10 {
-		writeError(w)
-		return
-	}
-	for i := 0; i < len(arr); i++ {
-		if len(arr[i]) != 10 {
+	// the prover only fails on an invalid board, so broken rules are reported before proving
+	if err := ruleset.ValidateBoard(arr); err != nil {
+		var boardErr *battleships.BoardError
+		if errors.As(err, &boardErr) {
+			writeBoardError(w, boardErr)
+		} else {
+			log.Println(err)
 			writeError(w)
-			return
 		}
+		return
 	}
-	fullString := ""
-	for i := 0; i < len(arr); i++ {
-		substr := ""
-		for j := 0; j < len(arr[i]); j++ {
-			substr = substr + strconv.Itoa(arr[i][j])
-		}
-		fullString = fullString + substr
-	}
-
-	log.Println(fullString)
-	stringForProver := "b" + fullString
-	salt := rand.Uint64()
-	saltString := strconv.FormatUint(salt, 16)
-//	out, err := exec.Command("./battleship -p " + stringForProver + " " + saltString).Output()
-//	log.Println(string(out))
-	//if cmd, e := exec.Run("./battleship -p", nil, nil, exec.DevNull, exec.Pipe, exec.MergeWithStdout); e == nil {
-       	//	b, _ := ioutil.ReadAll(cmd.Stdout)
-        //	fmt.Println("output: " + string(b))
-        //}
-	cmd := exec.Command("./battleship", "-p", stringForProver, saltString)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	err = cmd.Run()
+	salt, err := newSalt()
 	if err != nil {
 		log.Println(err)
 		writeError(w)
 		return
 	}
-	fmt.Printf("in all caps: %q\n", out.String())
-	testVerifier()
-	fullFile, err := ioutil.ReadFile("proof.txt")
-	if err != nil {
+
+	if jobs == nil {
+		log.Println("Job queue is not started")
 		writeError(w)
 		return
 	}
-	fullContent := string(fullFile)
-	writeResponse(w, fullContent, "")
+	// proving may take longer than a request, so the client polls the job
+	job, err := jobs.Submit(ruleset.Circuit, PrivateInputs{Board: arr, Salt: salt})
+	if err != nil {
+		log.Println(err)
+		writeJobError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	writeJob(w, http.StatusAccepted, job)
 }
 
-func testVerifier() {
-        cmd := exec.Command("./battleship", "-v")
-        var out bytes.Buffer
-        cmd.Stdout = &out
-        err := cmd.Run()
-        if err != nil {
-                log.Println(err)
-                return
-        }
-        fmt.Printf("in all caps: %q\n", out.String())
-
-        outString := out.String()
-        log.Println(outString)
-        length := len(outString)
-        result := outString[length-5 : length-1]
-        fmt.Println(result)
-
+// newSalt returns a random 64 bit salt, it hides the board in the commitment
+func newSalt() (*big.Int, error) {
+	var salt [8]byte
+	if _, err := rand.Read(salt[:]); err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(salt[:]), nil
 }
 
 func writeError(w http.ResponseWriter) {
-	resp := proverResponse{true}
+	resp := proverResponse{Error: true}
+
+	js, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(js)
+}
+
+func writeBoardError(w http.ResponseWriter, boardErr *battleships.BoardError) {
+	resp := proverResponse{Error: true, Reason: boardErr.Error(), Violations: boardErr.Violations}
 
 	js, err := json.Marshal(resp)
 	if err != nil {
@@ -126,6 +113,7 @@ func writeError(w http.ResponseWriter) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
 	w.Write(js)
 }
 
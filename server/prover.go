@@ -0,0 +1,314 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/shamatar/go-snarks/battleships"
+	"github.com/shamatar/go-snarks/verifier"
+)
+
+// BattleshipCircuit is the circuit proving that a classic board is committed with a salt
+const BattleshipCircuit = battleships.ClassicCircuit
+
+var (
+	ErrUnknownCircuit = errors.New("Unknown circuit")
+	ErrUnknownBackend = errors.New("Unknown prover backend")
+	ErrMissingInputs  = errors.New("Board or salt is missing")
+)
+
+// PrivateInputs are the secrets a board is committed with,
+// every backend encodes them for its own stack
+type PrivateInputs struct {
+	// Board has 0 and 1 cells row by row
+	Board [][]int
+	// Salt hides the board in the commitment
+	Salt *big.Int
+}
+
+// validate checks that there is something to encode
+func (in PrivateInputs) validate() error {
+	if len(in.Board) == 0 || in.Salt == nil {
+		return ErrMissingInputs
+	}
+	return nil
+}
+
+// copy returns inputs sharing nothing with in
+func (in PrivateInputs) copy() PrivateInputs {
+	copied := PrivateInputs{}
+	if in.Board != nil {
+		copied.Board = make([][]int, len(in.Board))
+		for i, row := range in.Board {
+			copied.Board[i] = append([]int{}, row...)
+		}
+	}
+	if in.Salt != nil {
+		copied.Salt = new(big.Int).Set(in.Salt)
+	}
+	return copied
+}
+
+// ProofResult is a proof in the format of the proving stack with its public inputs
+type ProofResult struct {
+	Proof  string
+	Inputs verifier.Witness
+}
+
+// Prover proves knowledge of private inputs satisfying a circuit
+type Prover interface {
+	Prove(ctx context.Context, circuitID string, inputs PrivateInputs) (*ProofResult, error)
+}
+
+// circuitChecker is a Prover that tells in advance which circuits it proves
+type circuitChecker interface {
+	Supports(circuitID string) bool
+}
+
+// CheckCircuit returns ErrUnknownCircuit if the prover can not prove the
+// circuit, provers that can not tell in advance are assumed to prove it
+func CheckCircuit(p Prover, circuitID string) error {
+	if checker, ok := p.(circuitChecker); ok && !checker.Supports(circuitID) {
+		return fmt.Errorf("%w %q", ErrUnknownCircuit, circuitID)
+	}
+	return nil
+}
+
+// LibsnarkProver runs the libsnark battleship binary, which proves the
+// battleship circuit only. The board is passed as its cell digits after "b"
+// and the salt in hex
+type LibsnarkProver struct {
+	// Dir has the binary and the files it reads
+	Dir    string
+	Binary string
+	// ReadOnly are files of Dir the binary only reads, like the proving key.
+	// They are linked into workspaces, other files are copied
+	ReadOnly []string
+	// WorkspaceRoot is where workspaces are created, empty means the system temp directory
+	WorkspaceRoot string
+}
+
+// libsnarkProofFile is written by the battleship binary
+const libsnarkProofFile = "proof.txt"
+
+// Supports tells if the circuit is the battleship circuit
+func (p *LibsnarkProver) Supports(circuitID string) bool {
+	return circuitID == BattleshipCircuit
+}
+
+// libsnarkArguments encodes the inputs for the battleship binary
+func libsnarkArguments(inputs PrivateInputs) []string {
+	var board strings.Builder
+	board.WriteString("b")
+	for _, row := range inputs.Board {
+		for _, cell := range row {
+			board.WriteString(strconv.Itoa(cell))
+		}
+	}
+	return []string{"-p", board.String(), inputs.Salt.Text(16)}
+}
+
+func (p *LibsnarkProver) Prove(ctx context.Context, circuitID string, inputs PrivateInputs) (*ProofResult, error) {
+	if !p.Supports(circuitID) {
+		return nil, ErrUnknownCircuit
+	}
+	if err := inputs.validate(); err != nil {
+		return nil, err
+	}
+	binary, err := filepath.Abs(filepath.Join(p.Dir, p.Binary))
+	if err != nil {
+		return nil, err
+	}
+	ws, err := newWorkspace(p.WorkspaceRoot, p.Dir, p.ReadOnly, p.Binary, libsnarkProofFile)
+	if err != nil {
+		return nil, err
+	}
+	defer ws.Close()
+	out, err := ws.run(ctx, binary, libsnarkArguments(inputs)...)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Prover output: %q", out)
+	proof, err := ws.readFile(libsnarkProofFile)
+	if err != nil {
+		return nil, err
+	}
+	// battleship circuit has no public inputs yet
+	return &ProofResult{Proof: string(proof), Inputs: verifier.Witness{}}, nil
+}
+
+// ZoKratesProver runs the ZoKrates CLI. Every circuit is a subdirectory of
+// Dir with the compiled program "out" and "proving.key". Programs take every
+// board cell row by row and then the salt, each as a field element of its own
+type ZoKratesProver struct {
+	// Binary is the CLI, a bare name is looked up in PATH
+	Binary string
+	Dir    string
+	// Scheme is passed as --proving-scheme, empty means the ZoKrates default
+	Scheme string
+	// WorkspaceRoot is where workspaces are created, empty means the system temp directory
+	WorkspaceRoot string
+}
+
+// zokratesProofFile is written by generate-proof
+const zokratesProofFile = "proof.json"
+
+// zokratesReadOnly are files of a circuit directory ZoKrates only reads
+var zokratesReadOnly = []string{"out", "proving.key"}
+
+// Supports tells if the circuit is a subdirectory of Dir with the compiled program
+func (p *ZoKratesProver) Supports(circuitID string) bool {
+	if circuitID == "" || circuitID != filepath.Base(circuitID) || strings.HasPrefix(circuitID, ".") {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(p.Dir, circuitID, "out"))
+	return err == nil
+}
+
+// zokratesArguments encodes the inputs for compute-witness in decimal
+func zokratesArguments(inputs PrivateInputs) []string {
+	args := []string{"compute-witness", "-a"}
+	for _, row := range inputs.Board {
+		for _, cell := range row {
+			args = append(args, strconv.Itoa(cell))
+		}
+	}
+	return append(args, inputs.Salt.String())
+}
+
+func (p *ZoKratesProver) Prove(ctx context.Context, circuitID string, inputs PrivateInputs) (*ProofResult, error) {
+	if !p.Supports(circuitID) {
+		return nil, ErrUnknownCircuit
+	}
+	if err := inputs.validate(); err != nil {
+		return nil, err
+	}
+	circuitDir := filepath.Join(p.Dir, circuitID)
+	ws, err := newWorkspace(p.WorkspaceRoot, circuitDir, zokratesReadOnly, "witness", zokratesProofFile)
+	if err != nil {
+		return nil, err
+	}
+	defer ws.Close()
+	out, err := ws.run(ctx, p.Binary, zokratesArguments(inputs)...)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Witness output: %q", out)
+	args := []string{"generate-proof"}
+	if p.Scheme != "" {
+		args = append(args, "--proving-scheme", p.Scheme)
+	}
+	out, err = ws.run(ctx, p.Binary, args...)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Prover output: %q", out)
+	proof, err := ws.readFile(zokratesProofFile)
+	if err != nil {
+		return nil, err
+	}
+	_, public, err := verifier.ParseZoKratesProof(strings.NewReader(string(proof)))
+	if err != nil {
+		_, public, err = verifier.ParseZoKratesGroth16Proof(strings.NewReader(string(proof)))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ProofResult{Proof: string(proof), Inputs: public}, nil
+}
+
+// FakeProver returns the same proof for every circuit without proving
+// anything, it is meant for tests and demos
+type FakeProver struct {
+	Proof  string
+	Inputs verifier.Witness
+	// Err is returned instead of the proof if set
+	Err error
+}
+
+func (p *FakeProver) Prove(ctx context.Context, circuitID string, inputs PrivateInputs) (*ProofResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if p.Err != nil {
+		return nil, p.Err
+	}
+	return &ProofResult{Proof: p.Proof, Inputs: p.Inputs}, nil
+}
+
+// ProverConfig selects the prover backend: "libsnark", "zokrates" or "fake"
+type ProverConfig struct {
+	Backend string `json:"backend"`
+	// Binary and Dir default to the battleship binary in the working directory
+	// for libsnark and to "zokrates" in PATH with circuits in the working
+	// directory for zokrates
+	Binary string `json:"binary,omitempty"`
+	Dir    string `json:"dir,omitempty"`
+	// ReadOnly lists files of Dir the libsnark binary only reads
+	ReadOnly      []string `json:"readOnly,omitempty"`
+	Scheme        string   `json:"scheme,omitempty"`
+	WorkspaceRoot string   `json:"workspaceRoot,omitempty"`
+	// ProofFile is the proof returned by the fake prover
+	ProofFile string `json:"proofFile,omitempty"`
+	// VerifyingKey is the key /verify checks proofs with, see NewVerifier
+	VerifyingKey string `json:"verifyingKey,omitempty"`
+}
+
+// withDefault returns def for an unset value
+func withDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// LoadProverConfig reads a JSON prover configuration
+func LoadProverConfig(filename string) (ProverConfig, error) {
+	var config ProverConfig
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return config, err
+	}
+	err = json.Unmarshal(content, &config)
+	return config, err
+}
+
+// NewProver makes the prover described by the configuration
+func NewProver(config ProverConfig) (Prover, error) {
+	switch config.Backend {
+	case "", "libsnark":
+		return &LibsnarkProver{
+			Dir:           withDefault(config.Dir, "."),
+			Binary:        withDefault(config.Binary, "battleship"),
+			ReadOnly:      config.ReadOnly,
+			WorkspaceRoot: config.WorkspaceRoot,
+		}, nil
+	case "zokrates":
+		return &ZoKratesProver{
+			Binary:        withDefault(config.Binary, "zokrates"),
+			Dir:           withDefault(config.Dir, "."),
+			Scheme:        config.Scheme,
+			WorkspaceRoot: config.WorkspaceRoot,
+		}, nil
+	case "fake":
+		fake := &FakeProver{Inputs: verifier.Witness{}}
+		if config.ProofFile != "" {
+			proof, err := ioutil.ReadFile(config.ProofFile)
+			if err != nil {
+				return nil, err
+			}
+			fake.Proof = string(proof)
+		}
+		return fake, nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownBackend, config.Backend)
+}
@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeZoKrates checks that the circuit is linked into the workspace and
+// steps are run in order, the proof is copied from ZOKRATES_PROOF and the
+// arguments of compute-witness are kept in ZOKRATES_ARGS
+const fakeZoKrates = `#!/bin/sh
+case "$1" in
+compute-witness) cat out proving.key > /dev/null && echo "$@" > witness ;;
+generate-proof) cat witness > /dev/null && cp witness "${ZOKRATES_ARGS:-/dev/null}" && cp "$ZOKRATES_PROOF" proof.json ;;
+*) exit 1 ;;
+esac
+`
+
+// installFakeZoKrates makes a ZoKrates prover of the battleship circuit running
+// the fake CLI, proofs are copied from the file
+func installFakeZoKrates(t *testing.T, proofFile string) (*ZoKratesProver, func()) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ZoKrates is a shell script")
+	}
+	dir, err := ioutil.TempDir("", "zokrates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	circuit := filepath.Join(dir, "circuits", BattleshipCircuit)
+	if err := os.MkdirAll(circuit, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"out": "program", "proving.key": "key"} {
+		if err := ioutil.WriteFile(filepath.Join(circuit, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	binary := filepath.Join(dir, "zokrates")
+	if err := ioutil.WriteFile(binary, []byte(fakeZoKrates), 0755); err != nil {
+		t.Fatal(err)
+	}
+	proofFile, _ = filepath.Abs(proofFile)
+	os.Setenv("ZOKRATES_PROOF", proofFile)
+	os.Setenv("ZOKRATES_ARGS", filepath.Join(dir, "args"))
+	p := &ZoKratesProver{Binary: binary, Dir: filepath.Join(dir, "circuits")}
+	return p, func() {
+		os.Unsetenv("ZOKRATES_PROOF")
+		os.Unsetenv("ZOKRATES_ARGS")
+		os.RemoveAll(dir)
+	}
+}
+
+func TestNewProver(t *testing.T) {
+	for backend, expected := range map[string]Prover{
+		"":         &LibsnarkProver{},
+		"libsnark": &LibsnarkProver{},
+		"zokrates": &ZoKratesProver{},
+		"fake":     &FakeProver{},
+	} {
+		p, err := NewProver(ProverConfig{Backend: backend})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprintf("%T", p) != fmt.Sprintf("%T", expected) {
+			t.Fatalf("%q: got %T, expected %T", backend, p, expected)
+		}
+	}
+	if _, err := NewProver(ProverConfig{Backend: "gnark"}); !errors.Is(err, ErrUnknownBackend) {
+		t.Fatalf("expected %v, got %v", ErrUnknownBackend, err)
+	}
+	if _, err := NewVerifier(ProverConfig{Backend: "gnark"}, BattleshipCircuit); !errors.Is(err, ErrUnknownBackend) {
+		t.Fatalf("expected %v, got %v", ErrUnknownBackend, err)
+	}
+	if _, err := NewVerifier(ProverConfig{Backend: "zokrates", VerifyingKey: "../vk_key.txt"}, BattleshipCircuit); err == nil {
+		t.Fatal("libsnark key is accepted as a ZoKrates key")
+	}
+	if _, err := NewProver(ProverConfig{Backend: "fake", ProofFile: "missing.txt"}); err == nil {
+		t.Fatal("missing proof file is accepted")
+	}
+}
+
+func TestCheckCircuit(t *testing.T) {
+	libsnark := &LibsnarkProver{}
+	if err := CheckCircuit(libsnark, BattleshipCircuit); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckCircuit(libsnark, "battleship-4x4"); !errors.Is(err, ErrUnknownCircuit) {
+		t.Fatalf("expected %v, got %v", ErrUnknownCircuit, err)
+	}
+	if err := CheckCircuit(&ZoKratesProver{Dir: "."}, "missing"); !errors.Is(err, ErrUnknownCircuit) {
+		t.Fatalf("expected %v, got %v", ErrUnknownCircuit, err)
+	}
+	if err := CheckCircuit(&FakeProver{}, "battleship-4x4"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFakeProver(t *testing.T) {
+	p, err := NewProver(ProverConfig{Backend: "fake", ProofFile: "../proof.txt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer startJobs(p)()
+	arr, _ := board(1)
+	resp, err := prove(arr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := ioutil.ReadFile("../proof.txt")
+	if resp.Proof != string(expected) {
+		t.Fatalf("unexpected proof %q", resp.Proof)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p.Prove(ctx, BattleshipCircuit, PrivateInputs{}); err != context.Canceled {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestZoKratesProver(t *testing.T) {
+	p, cleanup := installFakeZoKrates(t, "../verifier/zokrates_proof.json")
+	defer cleanup()
+	result, err := p.Prove(context.Background(), BattleshipCircuit, PrivateInputs{Board: [][]int{{1, 0}}, Salt: big.NewInt(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := ioutil.ReadFile("../verifier/zokrates_proof.json")
+	if result.Proof != string(expected) || len(result.Inputs) != 6 || result.Inputs[5].Int64() != 2 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	for _, circuitID := range []string{"missing", "..", "../circuits/battleship", ""} {
+		if _, err := p.Prove(context.Background(), circuitID, PrivateInputs{}); err != ErrUnknownCircuit {
+			t.Fatalf("%q: expected %v, got %v", circuitID, ErrUnknownCircuit, err)
+		}
+	}
+	if _, err := p.Prove(context.Background(), BattleshipCircuit, PrivateInputs{Board: [][]int{{1}}}); err != ErrMissingInputs {
+		t.Fatalf("expected %v, got %v", ErrMissingInputs, err)
+	}
+}
+
+func TestProveHanderZoKrates(t *testing.T) {
+	proveWithZoKrates(t, "../verifier/zokrates_proof.json", "../verifier/zokrates_verification.key")
+	proveWithZoKrates(t, "../verifier/zokrates_g16_proof.json", "../verifier/zokrates_g16_verification.key")
+}
+
+// proveWithZoKrates runs ProveHander with the fake ZoKrates and checks the
+// proof of the job with VerifyHander and the key of the circuit
+func proveWithZoKrates(t *testing.T, proofFile, keyFile string) {
+	p, cleanup := installFakeZoKrates(t, proofFile)
+	defer cleanup()
+	defer startJobs(p)()
+	arr, _ := board(7)
+	job, err := prove(arr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every cell and the salt are field elements of their own
+	args, err := ioutil.ReadFile(os.Getenv("ZOKRATES_ARGS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := strings.Fields(string(args))
+	expected := []string{"compute-witness", "-a"}
+	for i := range arr {
+		for j := range arr[i] {
+			expected = append(expected, strconv.Itoa(arr[i][j]))
+		}
+	}
+	if len(fields) != len(expected)+1 || strings.Join(fields[:len(expected)], " ") != strings.Join(expected, " ") {
+		t.Fatalf("unexpected arguments %q", args)
+	}
+	if _, ok := new(big.Int).SetString(fields[len(expected)], 10); !ok {
+		t.Fatalf("salt %q is not a decimal field element", fields[len(expected)])
+	}
+
+	// /verify takes the proof of the job as it is
+	key, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(p.Dir, BattleshipCircuit, "verification.key"), key, 0644); err != nil {
+		t.Fatal(err)
+	}
+	v, err := NewVerifier(ProverConfig{Backend: "zokrates", Dir: p.Dir}, BattleshipCircuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetVerifier(v)
+	defer SetVerifier(nil)
+	if resp := verify(t, job.Proof); resp.Error || !resp.Valid {
+		t.Fatalf("%s is rejected: %+v", proofFile, resp)
+	}
+	libsnarkProof, _ := ioutil.ReadFile("../proof.txt")
+	if resp := verify(t, string(libsnarkProof)); !resp.Error || resp.Valid {
+		t.Fatalf("libsnark proof is not rejected: %+v", resp)
+	}
+}
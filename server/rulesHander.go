@@ -0,0 +1,28 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/shamatar/go-snarks/battleships"
+)
+
+// ruleset is the game variant boards are validated and proved for, it is set by SetRuleset
+var ruleset = battleships.ClassicRuleset()
+
+// SetRuleset sets the rules used by ProveHander and served by RulesHander
+func SetRuleset(r *battleships.Ruleset) {
+	ruleset = r
+}
+
+// RulesHander serves the ruleset, so clients lay out boards by the same rules
+func RulesHander(w http.ResponseWriter, r *http.Request) {
+	js, err := json.Marshal(ruleset)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(js)
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shamatar/go-snarks/battleships"
+)
+
+func TestRuleset(t *testing.T) {
+	small := &battleships.Ruleset{
+		Name:      "small",
+		Rows:      3,
+		Cols:      3,
+		Fleet:     map[int]int{1: 1, 2: 1},
+		Adjacency: battleships.NoTouching,
+		Circuit:   "battleship-3x3",
+	}
+	SetRuleset(small)
+	defer SetRuleset(battleships.ClassicRuleset())
+	defer startJobs(&FakeProver{Proof: "proof"})()
+
+	rec := httptest.NewRecorder()
+	RulesHander(rec, httptest.NewRequest("GET", "/rules", nil))
+	served := new(battleships.Ruleset)
+	if err := json.Unmarshal(rec.Body.Bytes(), served); err != nil {
+		t.Fatal(err)
+	}
+	if served.Rows != 3 || served.Fleet[2] != 1 || served.Circuit != small.Circuit {
+		t.Fatalf("unexpected ruleset %+v", served)
+	}
+
+	job, err := prove([][]int{{1, 1, 0}, {0, 0, 0}, {0, 0, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Circuit != small.Circuit {
+		t.Fatalf("board is proved by %s", job.Circuit)
+	}
+	// a classic board is not valid for the small game
+	arr, _ := board(0)
+	body, _ := json.Marshal(arr)
+	rec = httptest.NewRecorder()
+	ProveHander(rec, httptest.NewRequest("POST", "/prove", bytes.NewReader(body)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("classic board is accepted: %d %s", rec.Code, rec.Body)
+	}
+}
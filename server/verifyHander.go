@@ -1,26 +1,154 @@
 package server
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"log"
-	"fmt"
-	"bytes"
 	"net/http"
-	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/shamatar/go-snarks/battleships"
+	"github.com/shamatar/go-snarks/verifier"
 )
 
 type verificationResponse struct {
-	Error bool `json:"error"`
+	Error    bool   `json:"error"`
+	Valid    bool   `json:"valid"`
+	Equation int    `json:"equation,omitempty"`
+	Reason   string `json:"reason,omitempty"`
 }
 
-func VerifyHander(w http.ResponseWriter, r *http.Request) {
-	// if cmd, e := exec.Run("/bin/ls", nil, nil, exec.DevNull, exec.Pipe, exec.MergeWithStdout); e == nil {
-	//     b, _ := ioutil.ReadAll(cmd.Stdout)
-	//     println("output: " + string(b))
-	// }
+// ProofVerifier checks proofs in the format of a proving stack against the
+// verifying key of a circuit
+type ProofVerifier interface {
+	Verify(proof string) error
+}
+
+// proofVerifier checks proofs for VerifyHander, it is set by SetVerifier
+var proofVerifier ProofVerifier
+
+// SetVerifier sets the verifier used by VerifyHander
+func SetVerifier(v ProofVerifier) {
+	proofVerifier = v
+}
+
+// failedEquations maps verifier errors to the number of Pinocchio equation
+var failedEquations = map[error]int{
+	verifier.ErrKnowledgeA:       1,
+	verifier.ErrKnowledgeB:       2,
+	verifier.ErrKnowledgeC:       3,
+	verifier.ErrSameCoefficients: 4,
+	verifier.ErrDivisibility:     5,
+}
+
+// LoadVerifyingKey sets a libsnark verifier with the key dump to be used by VerifyHander
+func LoadVerifyingKey(filename string) error {
+	v, err := LoadLibsnarkVerifier(filename)
+	if err != nil {
+		return err
+	}
+	SetVerifier(v)
+	return nil
+}
+
+// NewVerifier makes the verifier of proofs the configured prover makes for the
+// circuit. The key is vk_key.txt in the working directory for libsnark and
+// fake provers and verification.key of the circuit for zokrates, unless the
+// configuration sets it
+func NewVerifier(config ProverConfig, circuitID string) (ProofVerifier, error) {
+	switch config.Backend {
+	case "", "libsnark", "fake":
+		v, err := LoadLibsnarkVerifier(withDefault(config.VerifyingKey, "vk_key.txt"))
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	case "zokrates":
+		key := filepath.Join(withDefault(config.Dir, "."), circuitID, "verification.key")
+		v, err := LoadZoKratesVerifier(withDefault(config.VerifyingKey, key))
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownBackend, config.Backend)
+}
+
+// LibsnarkVerifier checks libsnark text proofs of the battleship circuit
+type LibsnarkVerifier struct {
+	vk *verifier.PreparedVerifyingKey
+}
+
+// LoadLibsnarkVerifier parses and prepares a libsnark verifying key dump
+func LoadLibsnarkVerifier(filename string) (*LibsnarkVerifier, error) {
+	vk, err := battleships.LoadVerifyingKey(filename)
+	if err != nil {
+		return nil, err
+	}
+	prepared, err := verifier.NewPreparedVerifyingKey(vk)
+	if err != nil {
+		return nil, err
+	}
+	return &LibsnarkVerifier{prepared}, nil
+}
 
-	// dataJson := `[[1],[2],[3]]`
+func (v *LibsnarkVerifier) Verify(proof string) error {
+	parsed, err := verifier.ParseProofFromString(proof)
+	if err != nil {
+		return err
+	}
+	// battleship circuit has no public inputs yet.
+	// Proofs come from anyone, so points at infinity are not accepted
+	return v.vk.Verify(parsed, verifier.Witness{}, verifier.WithIdentityRejection())
+}
+
+// ZoKratesVerifier checks proof.json of ZoKrates with the public inputs it
+// lists. The proving scheme, PGHR13 or G16, is the one of the key
+type ZoKratesVerifier struct {
+	vk        *verifier.PreparedVerifyingKey
+	groth16VK *verifier.Groth16VerifyingKey
+}
+
+// LoadZoKratesVerifier parses verification.key of a circuit
+func LoadZoKratesVerifier(filename string) (*ZoKratesVerifier, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	vk, err := verifier.ParseZoKratesVerifyingKey(bytes.NewReader(content))
+	if err == nil {
+		prepared, err := verifier.NewPreparedVerifyingKey(vk)
+		if err != nil {
+			return nil, err
+		}
+		return &ZoKratesVerifier{vk: prepared}, nil
+	}
+	groth16VK, groth16Err := verifier.ParseZoKratesGroth16VerifyingKey(bytes.NewReader(content))
+	if groth16Err != nil {
+		return nil, fmt.Errorf("%s is neither a PGHR13 nor a G16 key: %v, %v", filename, err, groth16Err)
+	}
+	return &ZoKratesVerifier{groth16VK: groth16VK}, nil
+}
+
+func (v *ZoKratesVerifier) Verify(proof string) error {
+	if v.groth16VK != nil {
+		parsed, inputs, err := verifier.ParseZoKratesGroth16Proof(strings.NewReader(proof))
+		if err != nil {
+			return err
+		}
+		return verifier.VerifyGroth16(v.groth16VK, parsed, inputs)
+	}
+	parsed, inputs, err := verifier.ParseZoKratesProof(strings.NewReader(proof))
+	if err != nil {
+		return err
+	}
+	return v.vk.Verify(parsed, inputs, verifier.WithIdentityRejection())
+}
+
+func VerifyHander(w http.ResponseWriter, r *http.Request) {
 	var req verificationRequest
 	decoder := json.NewDecoder(r.Body)
 	err := decoder.Decode(&req)
@@ -28,41 +156,24 @@ func VerifyHander(w http.ResponseWriter, r *http.Request) {
 		writeError(w)
 		return
 	}
-
-	log.Printf("Unmarshaled: %v", req)
-
-	d1 := []byte(req.Proof)
-	err = ioutil.WriteFile("proof.txt", d1, 0644)
-	if err != nil {
+	if proofVerifier == nil {
+		log.Println("Verifying key is not loaded")
 		writeError(w)
 		return
 	}
-	cmd := exec.Command("./battleship", "-v")
-        var out bytes.Buffer
-        cmd.Stdout = &out
-        err = cmd.Run()
-        if err != nil {
-                log.Println(err)
-                writeError(w)
-                return
-        }
-        fmt.Printf("in all caps: %q\n", out.String())
-
-	outString := out.String()
-	log.Println(outString)
-	length := len(outString)
-	result := outString[length-5 : length-1]
-	fmt.Println(result)
-	if result == "PASS" {
-		writeSuccess(w)
+
+	if err := proofVerifier.Verify(req.Proof); err != nil {
+		writeVerification(w, verificationResponse{
+			Error:    true,
+			Equation: failedEquations[err],
+			Reason:   err.Error(),
+		})
 		return
 	}
-	writeError(w)
+	writeVerification(w, verificationResponse{Valid: true})
 }
 
-func writeSuccess(w http.ResponseWriter) {
-	resp := proverResponse{false}
-
+func writeVerification(w http.ResponseWriter, resp verificationResponse) {
 	js, err := json.Marshal(resp)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func verify(t *testing.T, proof string) verificationResponse {
+	body, _ := json.Marshal(verificationRequest{Proof: proof})
+	rec := httptest.NewRecorder()
+	VerifyHander(rec, httptest.NewRequest("POST", "/verify", bytes.NewReader(body)))
+	var resp verificationResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestVerifyHander(t *testing.T) {
+	if err := LoadVerifyingKey("../vk_key.txt"); err != nil {
+		t.Fatal(err)
+	}
+	proof, err := ioutil.ReadFile("../proof.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp := verify(t, string(proof))
+	if resp.Error || !resp.Valid {
+		t.Fatalf("valid proof is rejected: %+v", resp)
+	}
+
+	// corrupt proof.H with proof.K
+	lines := strings.Split(string(proof), "\n")
+	lines[3] = lines[4]
+	resp = verify(t, strings.Join(lines, "\n"))
+	if !resp.Error || resp.Valid || resp.Equation != 5 {
+		t.Fatalf("corrupted proof is not rejected properly: %+v", resp)
+	}
+
+	// malformed bodies are reported, not panicked on
+	for _, malformed := range []string{"", "0", "0 1", lines[0], string(proof) + " 0 1 2"} {
+		resp = verify(t, malformed)
+		if !resp.Error || resp.Valid || resp.Reason == "" {
+			t.Fatalf("malformed proof %q is not rejected properly: %+v", malformed, resp)
+		}
+	}
+}
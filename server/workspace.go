@@ -0,0 +1,110 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// workspace is a temporary directory for a single prover run. Provers
+// read and write files in their working directory, so concurrent runs
+// sharing one directory would overwrite each other's proofs
+type workspace struct {
+	dir string
+}
+
+// newWorkspace creates a directory in root, empty root means the system temp
+// directory, and fills it with regular files of source except the skipped ones.
+// Read-only files are linked, the rest are copied, so that files written by
+// the prover never reach source
+func newWorkspace(root, source string, readOnly []string, skip ...string) (*workspace, error) {
+	source, err := filepath.Abs(source)
+	if err != nil {
+		return nil, err
+	}
+	files, err := ioutil.ReadDir(source)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := ioutil.TempDir(root, "prover")
+	if err != nil {
+		return nil, err
+	}
+	ws := &workspace{dir}
+	skipped := make(map[string]bool)
+	for _, name := range skip {
+		skipped[name] = true
+	}
+	linked := make(map[string]bool)
+	for _, name := range readOnly {
+		linked[name] = true
+	}
+	for _, file := range files {
+		if !file.Mode().IsRegular() || skipped[file.Name()] {
+			continue
+		}
+		from, to := filepath.Join(source, file.Name()), filepath.Join(dir, file.Name())
+		if linked[file.Name()] {
+			err = os.Symlink(from, to)
+		} else {
+			err = copyFile(from, to, file.Mode())
+		}
+		if err != nil {
+			ws.Close()
+			return nil, err
+		}
+	}
+	return ws, nil
+}
+
+// copyFile copies a regular file with the permissions
+func copyFile(from, to string, mode os.FileMode) error {
+	in, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode.Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// run executes the binary in the workspace and returns its output. A binary
+// given by path is resolved against the current directory, a bare name is
+// looked up in PATH
+func (ws *workspace) run(ctx context.Context, binary string, args ...string) (string, error) {
+	if strings.ContainsRune(binary, filepath.Separator) {
+		abs, err := filepath.Abs(binary)
+		if err != nil {
+			return "", err
+		}
+		binary = abs
+	}
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Dir = ws.dir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	return out.String(), err
+}
+
+// readFile returns a file written by the prover
+func (ws *workspace) readFile(name string) ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join(ws.dir, name))
+}
+
+// Close removes the workspace with everything written there
+func (ws *workspace) Close() error {
+	return os.RemoveAll(ws.dir)
+}
@@ -0,0 +1,43 @@
+package verifier
+
+import (
+	"fmt"
+)
+
+// BatchError lists indices of invalid proofs in a batch
+type BatchError struct {
+	Invalid []int
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("Batch has %d invalid proofs: %v", len(e.Invalid), e.Invalid)
+}
+
+// BatchVerify checks many proofs for the same key with a single final exponentiation.
+// Every equation of every proof is multiplied by its own random coefficient,
+// and all pairings that share a G2 element from the key are merged, so the
+// number of Miller loops is 6 + len(proofs).
+// If the batch does not pass it's split in halves to find invalid proofs,
+// that are returned as *BatchError
+func BatchVerify(vk *VerifyingKey, proofs []Proof, inputs []Witness, opts ...Option) error {
+	if err := vk.validate(); err != nil {
+		return err
+	}
+	return prepare(vk).BatchVerify(proofs, inputs, opts...)
+}
+
+// mergeSorted merges two sorted lists of indices
+func mergeSorted(a, b []int) []int {
+	result := make([]int, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		if a[0] < b[0] {
+			result = append(result, a[0])
+			a = a[1:]
+		} else {
+			result = append(result, b[0])
+			b = b[1:]
+		}
+	}
+	result = append(result, a...)
+	return append(result, b...)
+}
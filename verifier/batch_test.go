@@ -0,0 +1,49 @@
+package verifier
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func exampleBatch(t testing.TB, n int) (*VerifyingKey, []Proof, []Witness) {
+	vk, proof, witness := zokratesExample(t)
+	proofs := make([]Proof, n)
+	inputs := make([]Witness, n)
+	for i := range proofs {
+		proofs[i] = *proof
+		inputs[i] = append(Witness{}, witness...)
+	}
+	return vk, proofs, inputs
+}
+
+func TestBatchVerification(t *testing.T) {
+	vk, proofs, inputs := exampleBatch(t, 8)
+	if err := BatchVerify(vk, proofs, inputs); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBatchVerificationFindsInvalidProofs(t *testing.T) {
+	vk, proofs, inputs := exampleBatch(t, 8)
+	inputs[2][5] = big.NewInt(3)
+	proofs[5].H = proofs[5].K
+	inputs[6] = inputs[6][:3]
+
+	err := BatchVerify(vk, proofs, inputs)
+	batchErr, ok := err.(*BatchError)
+	if !ok {
+		t.Fatalf("expected *BatchError, got %v", err)
+	}
+	if !reflect.DeepEqual(batchErr.Invalid, []int{2, 5, 6}) {
+		t.Fatalf("invalid proofs are not found: %v", batchErr.Invalid)
+	}
+}
+
+func BenchmarkBatchVerification(b *testing.B) {
+	vk, proofs, inputs := exampleBatch(b, 16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchVerify(vk, proofs, inputs)
+	}
+}
@@ -0,0 +1,221 @@
+package verifier
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Encoders of the calls made to the contract written by WriteSolidityVerifier
+// and of the calls that contract makes to the EIP-196/197 precompiles.
+// Every value is a 32 bytes big-endian word, points are in bn256 Marshal layout
+
+// Addresses of the precompiles
+const (
+	EcAddAddress     = 6
+	EcMulAddress     = 7
+	EcPairingAddress = 8
+)
+
+// Gas costs of the precompiles after EIP-1108
+const (
+	EcAddGas             = 150
+	EcMulGas             = 6000
+	EcPairingBaseGas     = 45000
+	EcPairingPerPointGas = 34000
+)
+
+const (
+	ecPairingPairSize = 192
+	abiWordSize       = 32
+	selectorSize      = 4
+	// a, a_p, b, b_p, c, c_p, h and k
+	proofWords = 18
+)
+
+// PrecompileCall is a single call of ecAdd, ecMul or ecPairing
+type PrecompileCall struct {
+	Address byte
+	Input   []byte
+}
+
+// Gas returns the cost of the call
+func (c PrecompileCall) Gas() uint64 {
+	switch c.Address {
+	case EcAddAddress:
+		return EcAddGas
+	case EcMulAddress:
+		return EcMulGas
+	case EcPairingAddress:
+		return EcPairingBaseGas + EcPairingPerPointGas*uint64(len(c.Input)/ecPairingPairSize)
+	}
+	return 0
+}
+
+// Run executes the call the same way the precompile does
+func (c PrecompileCall) Run() ([]byte, error) {
+	switch c.Address {
+	case EcAddAddress:
+		return AddG1(c.Input)
+	case EcMulAddress:
+		return MulG1(c.Input)
+	case EcPairingAddress:
+		return PairingCheckBytes(c.Input)
+	}
+	return nil, fmt.Errorf("Unknown precompile %d", c.Address)
+}
+
+func ecAddCall(a, b *G1) PrecompileCall {
+	return PrecompileCall{Address: EcAddAddress, Input: append(a.Marshal(), b.Marshal()...)}
+}
+
+func ecMulCall(p *G1, scalar []byte) PrecompileCall {
+	return PrecompileCall{Address: EcMulAddress, Input: append(p.Marshal(), scalar...)}
+}
+
+func ecPairingCall(a []*G1, b []*G2) PrecompileCall {
+	input := make([]byte, 0, len(a)*ecPairingPairSize)
+	for i := range a {
+		input = append(input, a[i].Marshal()...)
+		input = append(input, b[i].Marshal()...)
+	}
+	return PrecompileCall{Address: EcPairingAddress, Input: input}
+}
+
+// VerificationCalls lists the precompile calls the Solidity verifier makes
+// for a proof, in order. The verifier stops at the first failed pairing,
+// so for a valid proof all of them are made
+func VerificationCalls(vk *VerifyingKey, proof *Proof, witness Witness) ([]PrecompileCall, error) {
+	if err := vk.validate(); err != nil {
+		return nil, err
+	}
+	if err := proof.validate(); err != nil {
+		return nil, err
+	}
+	if len(witness)+1 != len(vk.IC) {
+		return nil, ErrInvalidWitnessLength
+	}
+	inputs, err := EncodeWitness(witness)
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]PrecompileCall, 0, 2*len(witness)+8)
+	accumulator := new(G1).Set(vk.IC[0])
+	for i, w := range witness {
+		calls = append(calls, ecMulCall(vk.IC[i+1], inputs[i*abiWordSize:(i+1)*abiWordSize]))
+		term := new(G1).ScalarMult(vk.IC[i+1], w)
+		calls = append(calls, ecAddCall(accumulator, term))
+		accumulator.Add(accumulator, term)
+	}
+
+	G2Base := GetG2Base()
+	neg := func(p *G1) *G1 { return new(G1).Neg(p) }
+	calls = append(calls,
+		ecPairingCall([]*G1{proof.A, neg(proof.Ap)}, []*G2{vk.A, G2Base}),
+		ecPairingCall([]*G1{vk.B, neg(proof.Bp)}, []*G2{proof.B, G2Base}),
+		ecPairingCall([]*G1{proof.C, neg(proof.Cp)}, []*G2{vk.C, G2Base}))
+
+	withA := new(G1).Add(accumulator, proof.A)
+	t := new(G1).Add(withA, proof.C)
+	calls = append(calls,
+		ecAddCall(accumulator, proof.A),
+		ecAddCall(withA, proof.C),
+		ecPairingCall([]*G1{proof.K, neg(t), neg(vk.GammaBeta1)}, []*G2{vk.Gamma, vk.GammaBeta2, proof.B}),
+		ecAddCall(accumulator, proof.A),
+		ecPairingCall([]*G1{withA, neg(proof.H), neg(proof.C)}, []*G2{proof.B, vk.Z, G2Base}))
+	return calls, nil
+}
+
+// EncodeWitness encodes public inputs as uint256 words
+func EncodeWitness(witness Witness) ([]byte, error) {
+	encoded := make([]byte, 0, len(witness)*abiWordSize)
+	for _, w := range witness {
+		if !inRange(w) {
+			return nil, ErrWitnessOutOfRange
+		}
+		word, _ := padBigInt(w)
+		encoded = append(encoded, word...)
+	}
+	return encoded, nil
+}
+
+// functionSelector is the first 4 bytes of keccak256 of the function signature
+func functionSelector(signature string) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write([]byte(signature))
+	return hash.Sum(nil)[:selectorSize]
+}
+
+// verifyProofSignature is the signature of verifyProof for the number of public inputs
+func verifyProofSignature(inputs int) string {
+	arguments := []string{
+		"uint256[2]", "uint256[2]", "uint256[2][2]", "uint256[2]",
+		"uint256[2]", "uint256[2]", "uint256[2]", "uint256[2]",
+	}
+	if inputs != 0 {
+		arguments = append(arguments, fmt.Sprintf("uint256[%d]", inputs))
+	}
+	return "verifyProof(" + strings.Join(arguments, ",") + ")"
+}
+
+// EncodeProof encodes a proof as arguments a, a_p, b, b_p, c, c_p, h, k of verifyProof
+func EncodeProof(proof *Proof) ([]byte, error) {
+	if err := proof.validate(); err != nil {
+		return nil, err
+	}
+	encoded := make([]byte, 0, proofWords*abiWordSize)
+	for _, p := range []interface{ Marshal() []byte }{
+		proof.A, proof.Ap, proof.B, proof.Bp, proof.C, proof.Cp, proof.H, proof.K,
+	} {
+		encoded = append(encoded, p.Marshal()...)
+	}
+	return encoded, nil
+}
+
+// VerifyProofCalldata encodes a call of verifyProof of the Solidity verifier
+func VerifyProofCalldata(proof *Proof, witness Witness) ([]byte, error) {
+	encodedProof, err := EncodeProof(proof)
+	if err != nil {
+		return nil, err
+	}
+	inputs, err := EncodeWitness(witness)
+	if err != nil {
+		return nil, err
+	}
+	calldata := functionSelector(verifyProofSignature(len(witness)))
+	calldata = append(calldata, encodedProof...)
+	return append(calldata, inputs...), nil
+}
+
+// EncodeVerifyingKey encodes a key as ABI arguments
+// (uint256[2][2] a, uint256[2] b, uint256[2][2] c, uint256[2][2] gamma,
+// uint256[2] gammaBeta1, uint256[2][2] gammaBeta2, uint256[2][2] z, uint256[2][] ic),
+// for example to pass it to a contract constructor
+func EncodeVerifyingKey(vk *VerifyingKey) ([]byte, error) {
+	if err := vk.validate(); err != nil {
+		return nil, err
+	}
+	encoded := make([]byte, 0, (26+2*len(vk.IC))*abiWordSize)
+	for _, p := range []interface{ Marshal() []byte }{
+		vk.A, vk.B, vk.C, vk.Gamma, vk.GammaBeta1, vk.GammaBeta2, vk.Z,
+	} {
+		encoded = append(encoded, p.Marshal()...)
+	}
+	// dynamic ic is referenced by offset of its length from the start of arguments
+	offset := len(encoded) + abiWordSize
+	encoded = appendWord(encoded, uint64(offset))
+	encoded = appendWord(encoded, uint64(len(vk.IC)))
+	for _, p := range vk.IC {
+		encoded = append(encoded, p.Marshal()...)
+	}
+	return encoded, nil
+}
+
+func appendWord(data []byte, v uint64) []byte {
+	word := make([]byte, abiWordSize)
+	binary.BigEndian.PutUint64(word[abiWordSize-8:], v)
+	return append(data, word...)
+}
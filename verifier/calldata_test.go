@@ -0,0 +1,108 @@
+package verifier
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestPairingCheckBytes(t *testing.T) {
+	// empty input is a successful check
+	result, err := PairingCheckBytes(nil)
+	if err != nil || result[31] != 1 {
+		t.Fatalf("empty pairing check has failed: %x, %v", result, err)
+	}
+
+	// e(2 * g1, g2) * e(-g1, 2 * g2) == 1
+	two := big.NewInt(2)
+	data := append(new(G1).ScalarBaseMult(two).Marshal(), GetG2Base().Marshal()...)
+	data = append(data, new(G1).Neg(GetG1Base()).Marshal()...)
+	data = append(data, new(G2).ScalarBaseMult(two).Marshal()...)
+	result, err = PairingCheckBytes(data)
+	if err != nil || len(result) != 32 || result[31] != 1 {
+		t.Fatalf("pairing check has failed: %x, %v", result, err)
+	}
+	result, _ = PairingCheckBytes(data[:192])
+	if !bytes.Equal(result, make([]byte, 32)) {
+		t.Fatalf("expected zero word, got %x", result)
+	}
+
+	if _, err := PairingCheckBytes(data[:100]); err == nil {
+		t.Fatal("data of invalid length is accepted")
+	}
+	data[0] ^= 1
+	if _, err := PairingCheckBytes(data); err == nil {
+		t.Fatal("point not on curve is accepted")
+	}
+}
+
+func TestVerificationCalls(t *testing.T) {
+	vk, proof, witness := zokratesExample(t)
+	calls, err := VerificationCalls(vk, proof, witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gas uint64
+	pairings := 0
+	for _, call := range calls {
+		output, err := call.Run()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if call.Address == EcPairingAddress {
+			pairings++
+			if output[31] != 1 {
+				t.Fatalf("pairing check %d has failed", pairings)
+			}
+		}
+		gas += call.Gas()
+	}
+	// 6 ecMul and 9 ecAdd, 3 pairings of 2 points and 2 of 3 points
+	expected := uint64(6*EcMulGas + 9*EcAddGas + 5*EcPairingBaseGas + 12*EcPairingPerPointGas)
+	if pairings != 5 || gas != expected {
+		t.Fatalf("expected 5 pairings and %d gas, got %d and %d", expected, pairings, gas)
+	}
+
+	if _, err := VerificationCalls(vk, proof, witness[1:]); err != ErrInvalidWitnessLength {
+		t.Fatalf("expected %v, got %v", ErrInvalidWitnessLength, err)
+	}
+}
+
+func TestCalldata(t *testing.T) {
+	if selector := hex.EncodeToString(functionSelector("transfer(address,uint256)")); selector != "a9059cbb" {
+		t.Fatalf("wrong selector %s", selector)
+	}
+
+	vk, proof, witness := zokratesExample(t)
+	calldata, err := VerifyProofCalldata(proof, witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calldata) != 4+(18+6)*32 {
+		t.Fatalf("unexpected calldata length %d", len(calldata))
+	}
+	// b is passed as [[x.c1, x.c0], [y.c1, y.c0]], just as the contract expects
+	if !bytes.Equal(calldata[4+4*32:4+8*32], proof.B.Marshal()) {
+		t.Fatal("b is not encoded in precompile layout")
+	}
+	if new(big.Int).SetBytes(calldata[len(calldata)-32:]).Cmp(witness[5]) != 0 {
+		t.Fatal("last input is not encoded")
+	}
+
+	witness[0] = new(big.Int).Set(Order)
+	if _, err := VerifyProofCalldata(proof, witness); err != ErrWitnessOutOfRange {
+		t.Fatalf("expected %v, got %v", ErrWitnessOutOfRange, err)
+	}
+
+	encodedVK, err := EncodeVerifyingKey(vk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(encodedVK) != (26+2*len(vk.IC))*32 {
+		t.Fatalf("unexpected key length %d", len(encodedVK))
+	}
+	if offset := new(big.Int).SetBytes(encodedVK[24*32 : 25*32]); offset.Int64() != 25*32 {
+		t.Fatalf("unexpected offset of IC %v", offset)
+	}
+}
@@ -0,0 +1,565 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shamatar/go-snarks/curve"
+)
+
+// VerifyingKey and Proof hold points of cloudflare bn256. The types below hold
+// points of any curve.Curve, so the same equations can be checked on other
+// curves and implementations. The verifiers of VerifyingKey wrap its points
+// for curve.BN254 and run the same code.
+// Points of a proof must be made by the curve of the key, otherwise
+// verification fails with ErrCurveMismatch
+
+// CurveVerifyingKey is VerifyingKey with points of the Curve
+type CurveVerifyingKey struct {
+	Curve      curve.Curve
+	A          curve.G2
+	B          curve.G1
+	C          curve.G2
+	Gamma      curve.G2
+	GammaBeta1 curve.G1
+	GammaBeta2 curve.G2
+	Z          curve.G2
+	IC         []curve.G1
+}
+
+// CurveProof is Proof with points of some curve.Curve
+type CurveProof struct {
+	A  curve.G1
+	Ap curve.G1
+	B  curve.G2
+	Bp curve.G1
+	C  curve.G1
+	Cp curve.G1
+	K  curve.G1
+	H  curve.G1
+}
+
+// CurveGroth16VerifyingKey is Groth16VerifyingKey with points of the Curve.
+// e(Alpha, Beta) can not be moved between implementations, so Alpha and Beta are required
+type CurveGroth16VerifyingKey struct {
+	Curve curve.Curve
+	Alpha curve.G1
+	Beta  curve.G2
+	Gamma curve.G2
+	Delta curve.G2
+	IC    []curve.G1
+}
+
+// CurveGroth16Proof is Groth16Proof with points of some curve.Curve
+type CurveGroth16Proof struct {
+	A curve.G1
+	B curve.G2
+	C curve.G1
+}
+
+// curvePoints moves points to a curve in order, stopping at the first error
+type curvePoints struct {
+	curve curve.Curve
+	err   error
+}
+
+func (c *curvePoints) g1(p interface{ Marshal() []byte }) curve.G1 {
+	if c.err != nil {
+		return nil
+	}
+	point, err := c.curve.UnmarshalG1(p.Marshal())
+	c.err = err
+	return point
+}
+
+func (c *curvePoints) g2(p interface{ Marshal() []byte }) curve.G2 {
+	if c.err != nil {
+		return nil
+	}
+	point, err := c.curve.UnmarshalG2(p.Marshal())
+	c.err = err
+	return point
+}
+
+func (c *curvePoints) g1Slice(points []*G1) []curve.G1 {
+	result := make([]curve.G1, len(points))
+	for i, p := range points {
+		result[i] = c.g1(p)
+	}
+	return result
+}
+
+// curveG1s wraps points of cloudflare bn256 for curve.BN254
+func curveG1s(points []*G1) []curve.G1 {
+	result := make([]curve.G1, len(points))
+	for i, p := range points {
+		result[i] = p
+	}
+	return result
+}
+
+// onBN254 wraps the points for curve.BN254 without copying them,
+// the key should be validated first
+func (vk *VerifyingKey) onBN254() *CurveVerifyingKey {
+	return &CurveVerifyingKey{
+		Curve:      curve.BN254,
+		A:          vk.A,
+		B:          vk.B,
+		C:          vk.C,
+		Gamma:      vk.Gamma,
+		GammaBeta1: vk.GammaBeta1,
+		GammaBeta2: vk.GammaBeta2,
+		Z:          vk.Z,
+		IC:         curveG1s(vk.IC),
+	}
+}
+
+// onBN254 wraps the points for curve.BN254 without copying them,
+// the proof should be validated first
+func (proof *Proof) onBN254() *CurveProof {
+	return &CurveProof{
+		A:  proof.A,
+		Ap: proof.Ap,
+		B:  proof.B,
+		Bp: proof.Bp,
+		C:  proof.C,
+		Cp: proof.Cp,
+		K:  proof.K,
+		H:  proof.H,
+	}
+}
+
+// onBN254 wraps the points for curve.BN254 without copying them,
+// the key should be validated and have Alpha and Beta
+func (vk *Groth16VerifyingKey) onBN254() *CurveGroth16VerifyingKey {
+	return &CurveGroth16VerifyingKey{
+		Curve: curve.BN254,
+		Alpha: vk.Alpha,
+		Beta:  vk.Beta,
+		Gamma: vk.Gamma,
+		Delta: vk.Delta,
+		IC:    curveG1s(vk.IC),
+	}
+}
+
+// onBN254 wraps the points for curve.BN254 without copying them,
+// the proof should be validated first
+func (proof *Groth16Proof) onBN254() *CurveGroth16Proof {
+	return &CurveGroth16Proof{A: proof.A, B: proof.B, C: proof.C}
+}
+
+// OnCurve moves the key to another implementation of BN254
+func (vk *VerifyingKey) OnCurve(c curve.Curve) (*CurveVerifyingKey, error) {
+	if err := vk.validate(); err != nil {
+		return nil, err
+	}
+	points := &curvePoints{curve: c}
+	moved := &CurveVerifyingKey{
+		Curve:      c,
+		A:          points.g2(vk.A),
+		B:          points.g1(vk.B),
+		C:          points.g2(vk.C),
+		Gamma:      points.g2(vk.Gamma),
+		GammaBeta1: points.g1(vk.GammaBeta1),
+		GammaBeta2: points.g2(vk.GammaBeta2),
+		Z:          points.g2(vk.Z),
+		IC:         points.g1Slice(vk.IC),
+	}
+	if points.err != nil {
+		return nil, points.err
+	}
+	return moved, nil
+}
+
+// OnCurve moves the proof to another implementation of BN254
+func (proof *Proof) OnCurve(c curve.Curve) (*CurveProof, error) {
+	if err := proof.validate(); err != nil {
+		return nil, err
+	}
+	points := &curvePoints{curve: c}
+	moved := &CurveProof{
+		A:  points.g1(proof.A),
+		Ap: points.g1(proof.Ap),
+		B:  points.g2(proof.B),
+		Bp: points.g1(proof.Bp),
+		C:  points.g1(proof.C),
+		Cp: points.g1(proof.Cp),
+		K:  points.g1(proof.K),
+		H:  points.g1(proof.H),
+	}
+	if points.err != nil {
+		return nil, points.err
+	}
+	return moved, nil
+}
+
+// OnCurve moves the key to another implementation of BN254
+func (vk *Groth16VerifyingKey) OnCurve(c curve.Curve) (*CurveGroth16VerifyingKey, error) {
+	if err := vk.validate(); err != nil {
+		return nil, err
+	}
+	if vk.Alpha == nil || vk.Beta == nil {
+		return nil, errors.New("Key without Alpha and Beta can not be moved")
+	}
+	points := &curvePoints{curve: c}
+	moved := &CurveGroth16VerifyingKey{
+		Curve: c,
+		Alpha: points.g1(vk.Alpha),
+		Beta:  points.g2(vk.Beta),
+		Gamma: points.g2(vk.Gamma),
+		Delta: points.g2(vk.Delta),
+		IC:    points.g1Slice(vk.IC),
+	}
+	if points.err != nil {
+		return nil, points.err
+	}
+	return moved, nil
+}
+
+// OnCurve moves the proof to another implementation of BN254
+func (proof *Groth16Proof) OnCurve(c curve.Curve) (*CurveGroth16Proof, error) {
+	if err := proof.validate(); err != nil {
+		return nil, err
+	}
+	points := &curvePoints{curve: c}
+	moved := &CurveGroth16Proof{
+		A: points.g1(proof.A),
+		B: points.g2(proof.B),
+		C: points.g1(proof.C),
+	}
+	if points.err != nil {
+		return nil, points.err
+	}
+	return moved, nil
+}
+
+// ErrCurveMismatch is returned if points are not made by the curve of the key
+var ErrCurveMismatch = errors.New("Points are not on the curve of the key")
+
+// onCurve tells if all the points are made by the curve
+func onCurve(c curve.Curve, g1 []curve.G1, g2 []curve.G2) bool {
+	for _, p := range g1 {
+		if !c.IsG1(p) {
+			return false
+		}
+	}
+	for _, p := range g2 {
+		if !c.IsG2(p) {
+			return false
+		}
+	}
+	return true
+}
+
+func (vk *CurveVerifyingKey) validate() error {
+	if vk == nil || vk.Curve == nil || vk.A == nil || vk.B == nil || vk.C == nil || vk.Gamma == nil ||
+		vk.GammaBeta1 == nil || vk.GammaBeta2 == nil || vk.Z == nil || len(vk.IC) == 0 {
+		return ErrInvalidVerifyingKey
+	}
+	for _, p := range vk.IC {
+		if p == nil {
+			return ErrInvalidVerifyingKey
+		}
+	}
+	g1 := append([]curve.G1{vk.B, vk.GammaBeta1}, vk.IC...)
+	if !onCurve(vk.Curve, g1, []curve.G2{vk.A, vk.C, vk.Gamma, vk.GammaBeta2, vk.Z}) {
+		return ErrCurveMismatch
+	}
+	return nil
+}
+
+func (proof *CurveProof) validate() error {
+	if proof == nil || proof.A == nil || proof.Ap == nil || proof.B == nil || proof.Bp == nil ||
+		proof.C == nil || proof.Cp == nil || proof.K == nil || proof.H == nil {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// validateOn checks that the proof is complete and made by the curve of the key
+func (proof *CurveProof) validateOn(c curve.Curve) error {
+	if err := proof.validate(); err != nil {
+		return err
+	}
+	g1 := []curve.G1{proof.A, proof.Ap, proof.Bp, proof.C, proof.Cp, proof.K, proof.H}
+	if !onCurve(c, g1, []curve.G2{proof.B}) {
+		return ErrCurveMismatch
+	}
+	return nil
+}
+
+func (vk *CurveGroth16VerifyingKey) validate() error {
+	if vk == nil || vk.Curve == nil || vk.Alpha == nil || vk.Beta == nil || vk.Gamma == nil ||
+		vk.Delta == nil || len(vk.IC) == 0 {
+		return ErrInvalidVerifyingKey
+	}
+	for _, p := range vk.IC {
+		if p == nil {
+			return ErrInvalidVerifyingKey
+		}
+	}
+	if !onCurve(vk.Curve, append([]curve.G1{vk.Alpha}, vk.IC...), []curve.G2{vk.Beta, vk.Gamma, vk.Delta}) {
+		return ErrCurveMismatch
+	}
+	return nil
+}
+
+func (proof *CurveGroth16Proof) validate() error {
+	if proof == nil || proof.A == nil || proof.B == nil || proof.C == nil {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// validateOn checks that the proof is complete and made by the curve of the key
+func (proof *CurveGroth16Proof) validateOn(c curve.Curve) error {
+	if err := proof.validate(); err != nil {
+		return err
+	}
+	if !onCurve(c, []curve.G1{proof.A, proof.C}, []curve.G2{proof.B}) {
+		return ErrCurveMismatch
+	}
+	return nil
+}
+
+// hasIdentity tells if some of the points of the proof is at infinity
+func (proof *CurveProof) hasIdentity() bool {
+	return hasIdentity(proof.A, proof.Ap, proof.B, proof.Bp, proof.C, proof.Cp, proof.K, proof.H)
+}
+
+// hasIdentity tells if some of the points is at infinity
+func hasIdentity(points ...interface{ Marshal() []byte }) bool {
+	for _, p := range points {
+		if isInfinity(p.Marshal()) {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyOnCurve checks a Pinocchio proof on the curve of the key with the
+// same equations and options as Verify
+func VerifyOnCurve(vk *CurveVerifyingKey, proof *CurveProof, inputs Witness, opts ...Option) error {
+	o := newOptions(opts)
+	if err := vk.validate(); err != nil {
+		return err
+	}
+	if err := proof.validateOn(vk.Curve); err != nil {
+		return err
+	}
+	if o.rejectIdentity && proof.hasIdentity() {
+		return ErrIdentityPoint
+	}
+	return prepareCurve(vk).verify(context.Background(), proof, inputs, o)
+}
+
+// BatchVerifyOnCurve checks many proofs on the curve of the key the same way as BatchVerify does
+func BatchVerifyOnCurve(vk *CurveVerifyingKey, proofs []CurveProof, inputs []Witness, opts ...Option) error {
+	o := newOptions(opts)
+	if err := vk.validate(); err != nil {
+		return err
+	}
+	if len(proofs) != len(inputs) {
+		return fmt.Errorf("Got %d proofs, but %d witnesses", len(proofs), len(inputs))
+	}
+	checked := make([]*CurveProof, len(proofs))
+	for i := range proofs {
+		if proofs[i].validateOn(vk.Curve) == nil && !(o.rejectIdentity && proofs[i].hasIdentity()) {
+			checked[i] = &proofs[i]
+		}
+	}
+	return prepareCurve(vk).batchVerify(context.Background(), checked, inputs, o)
+}
+
+// VerifyGroth16OnCurve checks a Groth16 proof on the curve of the key.
+// Of the options only WithWitnessReduction, WithIdentityRejection and
+// WithWorkers make sense here
+func VerifyGroth16OnCurve(vk *CurveGroth16VerifyingKey, proof *CurveGroth16Proof, inputs Witness, opts ...Option) error {
+	o := newOptions(opts)
+	if err := vk.validate(); err != nil {
+		return err
+	}
+	if err := proof.validateOn(vk.Curve); err != nil {
+		return err
+	}
+	if o.rejectIdentity && hasIdentity(proof.A, proof.B, proof.C) {
+		return ErrIdentityPoint
+	}
+	inputs, err := checkWitnessOf(inputs, len(vk.IC), vk.Curve.Order(), o.reduceWitness)
+	if err != nil {
+		return err
+	}
+	return curveGroth16Verification(inputs, proof, vk, o.workers)
+}
+
+// binary format, the same as of VerifyingKey and Proof
+// with points of the size of the curve
+
+// curveReader is binaryReader for points of the curve
+type curveReader struct {
+	binaryReader
+	curve curve.Curve
+}
+
+func (r *curveReader) g1() curve.G1 {
+	data := r.take(r.curve.G1Size())
+	if r.err != nil {
+		return nil
+	}
+	p, err := r.curve.UnmarshalG1(data)
+	r.err = err
+	return p
+}
+
+func (r *curveReader) g2() curve.G2 {
+	data := r.take(r.curve.G2Size())
+	if r.err != nil {
+		return nil
+	}
+	p, err := r.curve.UnmarshalG2(data)
+	r.err = err
+	return p
+}
+
+// g1Slice reads the number of points and the points
+func (r *curveReader) g1Slice() []curve.G1 {
+	n := r.uint32()
+	if r.err == nil && uint64(n)*uint64(r.curve.G1Size()) != uint64(len(r.data)) {
+		r.err = errors.New("Invalid number of IC points")
+	}
+	if r.err != nil {
+		return nil
+	}
+	points := make([]curve.G1, 0, n)
+	for i := uint32(0); i < n && r.err == nil; i++ {
+		points = append(points, r.g1())
+	}
+	return points
+}
+
+func appendPoints(data []byte, points ...interface{ Marshal() []byte }) []byte {
+	for _, p := range points {
+		data = append(data, p.Marshal()...)
+	}
+	return data
+}
+
+func appendG1Slice(data []byte, points []curve.G1) []byte {
+	data = appendUint32(data, uint32(len(points)))
+	for _, p := range points {
+		data = append(data, p.Marshal()...)
+	}
+	return data
+}
+
+// MarshalBinary encodes the key as VerifyingKey.MarshalBinary does
+func (vk *CurveVerifyingKey) MarshalBinary() ([]byte, error) {
+	if err := vk.validate(); err != nil {
+		return nil, err
+	}
+	data := appendPoints(nil, vk.A, vk.B, vk.C, vk.Gamma, vk.GammaBeta1, vk.GammaBeta2, vk.Z)
+	return appendG1Slice(data, vk.IC), nil
+}
+
+// UnmarshalCurveVerifyingKey decodes the key written by MarshalBinary
+func UnmarshalCurveVerifyingKey(c curve.Curve, data []byte) (*CurveVerifyingKey, error) {
+	r := &curveReader{binaryReader: binaryReader{data: data}, curve: c}
+	vk := &CurveVerifyingKey{
+		Curve:      c,
+		A:          r.g2(),
+		B:          r.g1(),
+		C:          r.g2(),
+		Gamma:      r.g2(),
+		GammaBeta1: r.g1(),
+		GammaBeta2: r.g2(),
+		Z:          r.g2(),
+	}
+	vk.IC = r.g1Slice()
+	if err := r.finish(); err != nil {
+		return nil, err
+	}
+	if err := vk.validate(); err != nil {
+		return nil, err
+	}
+	return vk, nil
+}
+
+// MarshalBinary encodes the proof as Proof.MarshalBinary does
+func (proof *CurveProof) MarshalBinary() ([]byte, error) {
+	if err := proof.validate(); err != nil {
+		return nil, err
+	}
+	return appendPoints(nil, proof.A, proof.Ap, proof.B, proof.Bp, proof.C, proof.Cp, proof.H, proof.K), nil
+}
+
+// UnmarshalCurveProof decodes the proof written by MarshalBinary
+func UnmarshalCurveProof(c curve.Curve, data []byte) (*CurveProof, error) {
+	r := &curveReader{binaryReader: binaryReader{data: data}, curve: c}
+	proof := &CurveProof{
+		A:  r.g1(),
+		Ap: r.g1(),
+		B:  r.g2(),
+		Bp: r.g1(),
+		C:  r.g1(),
+		Cp: r.g1(),
+		H:  r.g1(),
+		K:  r.g1(),
+	}
+	if err := r.finish(); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// MarshalBinary encodes the key as Alpha, Beta, Gamma, Delta,
+// number of IC points and IC points
+func (vk *CurveGroth16VerifyingKey) MarshalBinary() ([]byte, error) {
+	if err := vk.validate(); err != nil {
+		return nil, err
+	}
+	data := appendPoints(nil, vk.Alpha, vk.Beta, vk.Gamma, vk.Delta)
+	return appendG1Slice(data, vk.IC), nil
+}
+
+// UnmarshalCurveGroth16VerifyingKey decodes the key written by MarshalBinary
+func UnmarshalCurveGroth16VerifyingKey(c curve.Curve, data []byte) (*CurveGroth16VerifyingKey, error) {
+	r := &curveReader{binaryReader: binaryReader{data: data}, curve: c}
+	vk := &CurveGroth16VerifyingKey{
+		Curve: c,
+		Alpha: r.g1(),
+		Beta:  r.g2(),
+		Gamma: r.g2(),
+		Delta: r.g2(),
+	}
+	vk.IC = r.g1Slice()
+	if err := r.finish(); err != nil {
+		return nil, err
+	}
+	if err := vk.validate(); err != nil {
+		return nil, err
+	}
+	return vk, nil
+}
+
+// MarshalBinary encodes the proof as A, B, C
+func (proof *CurveGroth16Proof) MarshalBinary() ([]byte, error) {
+	if err := proof.validate(); err != nil {
+		return nil, err
+	}
+	return appendPoints(nil, proof.A, proof.B, proof.C), nil
+}
+
+// UnmarshalCurveGroth16Proof decodes the proof written by MarshalBinary
+func UnmarshalCurveGroth16Proof(c curve.Curve, data []byte) (*CurveGroth16Proof, error) {
+	r := &curveReader{binaryReader: binaryReader{data: data}, curve: c}
+	proof := &CurveGroth16Proof{
+		A: r.g1(),
+		B: r.g2(),
+		C: r.g1(),
+	}
+	if err := r.finish(); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
@@ -0,0 +1,258 @@
+package verifier
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/shamatar/go-snarks/curve"
+)
+
+// curveExample makes keys from random toxic waste and simulates valid Pinocchio
+// and Groth16 proofs on the curve, every point is its logarithm times a generator
+func curveExample(t *testing.T, c curve.Curve, witness Witness) (*CurveVerifyingKey, *CurveProof, *CurveGroth16VerifyingKey, *CurveGroth16Proof) {
+	order := c.Order()
+	random := func() *big.Int {
+		k, err := rand.Int(rand.Reader, order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return k
+	}
+	mul := func(a, b *big.Int) *big.Int {
+		return new(big.Int).Mod(new(big.Int).Mul(a, b), order)
+	}
+	add := func(a, b *big.Int) *big.Int {
+		return new(big.Int).Mod(new(big.Int).Add(a, b), order)
+	}
+	g1 := func(k *big.Int) curve.G1 { return c.ScalarMultG1(c.G1Generator(), k) }
+	g2 := func(k *big.Int) curve.G2 { return c.ScalarMultG2(c.G2Generator(), k) }
+
+	ic := make([]curve.G1, len(witness)+1)
+	accumulated := big.NewInt(0)
+	for i := range ic {
+		u := random()
+		ic[i] = g1(u)
+		if i != 0 {
+			u = mul(u, witness[i-1])
+		}
+		accumulated = add(accumulated, u)
+	}
+
+	// Pinocchio: every equation is linear in logarithms
+	a, b, cc, gamma, beta, z := random(), random(), random(), random(), random(), random()
+	proofA, proofB, proofH := random(), random(), random()
+	// (accumulated + A) * B == H * z + C
+	proofC := add(mul(add(accumulated, proofA), proofB), new(big.Int).Neg(mul(proofH, z)))
+	// K * gamma == (accumulated + A + C) * gamma * beta + gamma * beta * B
+	proofK := mul(beta, add(add(add(accumulated, proofA), proofC), proofB))
+	vk := &CurveVerifyingKey{
+		Curve:      c,
+		A:          g2(a),
+		B:          g1(b),
+		C:          g2(cc),
+		Gamma:      g2(gamma),
+		GammaBeta1: g1(mul(gamma, beta)),
+		GammaBeta2: g2(mul(gamma, beta)),
+		Z:          g2(z),
+		IC:         ic,
+	}
+	proof := &CurveProof{
+		A:  g1(proofA),
+		Ap: g1(mul(proofA, a)),
+		B:  g2(proofB),
+		Bp: g1(mul(proofB, b)),
+		C:  g1(proofC),
+		Cp: g1(mul(proofC, cc)),
+		H:  g1(proofH),
+		K:  g1(proofK),
+	}
+
+	// Groth16: A * B == alpha * beta + accumulated * gamma + C * delta
+	alpha, delta := random(), random()
+	groth16A, groth16B := random(), random()
+	groth16C := add(mul(groth16A, groth16B), new(big.Int).Neg(add(mul(alpha, beta), mul(accumulated, gamma))))
+	groth16C = mul(groth16C, new(big.Int).ModInverse(delta, order))
+	groth16VK := &CurveGroth16VerifyingKey{
+		Curve: c,
+		Alpha: g1(alpha),
+		Beta:  g2(beta),
+		Gamma: g2(gamma),
+		Delta: g2(delta),
+		IC:    ic,
+	}
+	groth16Proof := &CurveGroth16Proof{A: g1(groth16A), B: g2(groth16B), C: g1(groth16C)}
+	return vk, proof, groth16VK, groth16Proof
+}
+
+func TestVerifyOnCurve(t *testing.T) {
+	witness := Witness{big.NewInt(3), big.NewInt(5)}
+	for name, c := range curve.Curves {
+		vk, proof, groth16VK, groth16Proof := curveExample(t, c, witness)
+		if err := VerifyOnCurve(vk, proof, witness); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := VerifyGroth16OnCurve(groth16VK, groth16Proof, witness); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		wrongWitness := Witness{big.NewInt(3), big.NewInt(6)}
+		if err := VerifyOnCurve(vk, proof, wrongWitness); err != ErrSameCoefficients {
+			t.Fatalf("%s: expected %v, got %v", name, ErrSameCoefficients, err)
+		}
+		if err := VerifyOnCurve(vk, proof, witness, WithStrategy(AggregateStrategy), WithWorkers(2)); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := VerifyOnCurve(vk, proof, wrongWitness, WithStrategy(AggregateStrategy)); err != ErrSameCoefficients {
+			t.Fatalf("%s: expected %v, got %v", name, ErrSameCoefficients, err)
+		}
+		corrupted := *proof
+		corrupted.K = proof.A
+		err := BatchVerifyOnCurve(vk, []CurveProof{*proof, corrupted, {}, *proof}, []Witness{witness, witness, witness, wrongWitness})
+		if batchErr, ok := err.(*BatchError); !ok || fmt.Sprint(batchErr.Invalid) != "[1 2 3]" {
+			t.Fatalf("%s: unexpected batch error %v", name, err)
+		}
+		if err := VerifyGroth16OnCurve(groth16VK, groth16Proof, wrongWitness); err != ErrGroth16Check {
+			t.Fatalf("%s: expected %v, got %v", name, ErrGroth16Check, err)
+		}
+		aliased := Witness{new(big.Int).Add(witness[0], c.Order()), witness[1]}
+		if err := VerifyOnCurve(vk, proof, aliased); err != ErrWitnessOutOfRange {
+			t.Fatalf("%s: expected %v, got %v", name, ErrWitnessOutOfRange, err)
+		}
+		if err := VerifyGroth16OnCurve(groth16VK, groth16Proof, aliased, WithWitnessReduction()); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		// binary round trip
+		data, err := vk.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsedVK, err := UnmarshalCurveVerifyingKey(c, data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		data, _ = proof.MarshalBinary()
+		parsedProof, err := UnmarshalCurveProof(c, data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := VerifyOnCurve(parsedVK, parsedProof, witness); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		data, _ = groth16VK.MarshalBinary()
+		parsedGroth16VK, err := UnmarshalCurveGroth16VerifyingKey(c, data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		data, _ = groth16Proof.MarshalBinary()
+		parsedGroth16Proof, err := UnmarshalCurveGroth16Proof(c, data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := VerifyGroth16OnCurve(parsedGroth16VK, parsedGroth16Proof, witness); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if _, err := UnmarshalCurveGroth16Proof(c, data[1:]); err == nil {
+			t.Fatalf("%s: truncated proof is accepted", name)
+		}
+	}
+}
+
+func TestCurveMismatch(t *testing.T) {
+	witness := Witness{big.NewInt(3), big.NewInt(5)}
+	for keyName, keyCurve := range curve.Curves {
+		vk, _, groth16VK, _ := curveExample(t, keyCurve, witness)
+		for proofName, proofCurve := range curve.Curves {
+			if proofName == keyName {
+				continue
+			}
+			_, proof, _, groth16Proof := curveExample(t, proofCurve, witness)
+			if err := VerifyOnCurve(vk, proof, witness); err != ErrCurveMismatch {
+				t.Fatalf("%s proof, %s key: expected %v, got %v", proofName, keyName, ErrCurveMismatch, err)
+			}
+			err := BatchVerifyOnCurve(vk, []CurveProof{*proof}, []Witness{witness})
+			if batchErr, ok := err.(*BatchError); !ok || len(batchErr.Invalid) != 1 {
+				t.Fatalf("%s proof, %s key: unexpected error %v", proofName, keyName, err)
+			}
+			if err := VerifyGroth16OnCurve(groth16VK, groth16Proof, witness); err != ErrCurveMismatch {
+				t.Fatalf("%s proof, %s key: expected %v, got %v", proofName, keyName, ErrCurveMismatch, err)
+			}
+			mixed := *vk
+			mixed.Z = proof.B
+			if err := VerifyOnCurve(&mixed, proof, witness); err != ErrCurveMismatch {
+				t.Fatalf("%s point in %s key: expected %v, got %v", proofName, keyName, ErrCurveMismatch, err)
+			}
+		}
+	}
+}
+
+func TestOnCurve(t *testing.T) {
+	vk, proof, witness := zokratesExample(t)
+	vkData, _ := vk.MarshalBinary()
+	proofData, _ := proof.MarshalBinary()
+	for _, c := range []curve.Curve{curve.BN254, curve.BN254Google} {
+		curveVK, err := vk.OnCurve(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		curveProof, err := proof.OnCurve(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyOnCurve(curveVK, curveProof, witness, WithIdentityRejection()); err != nil {
+			t.Fatalf("%s: %v", c.Name(), err)
+		}
+		// binary formats are the same for BN254
+		data, _ := curveVK.MarshalBinary()
+		if !bytes.Equal(data, vkData) {
+			t.Fatalf("%s: key is encoded differently", c.Name())
+		}
+		data, _ = curveProof.MarshalBinary()
+		if !bytes.Equal(data, proofData) {
+			t.Fatalf("%s: proof is encoded differently", c.Name())
+		}
+	}
+	if _, err := vk.OnCurve(curve.BLS12381); err == nil {
+		t.Fatal("BN254 key is moved to BLS12-381")
+	}
+
+	groth16Witness := Witness{big.NewInt(1), big.NewInt(2)}
+	groth16VK, groth16Proof := groth16Example(t, groth16Witness)
+	curveVK, err := groth16VK.OnCurve(curve.BN254Google)
+	if err != nil {
+		t.Fatal(err)
+	}
+	curveProof, err := groth16Proof.OnCurve(curve.BN254Google)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyGroth16OnCurve(curveVK, curveProof, groth16Witness); err != nil {
+		t.Fatal(err)
+	}
+	groth16VK.Alpha = nil
+	if _, err := groth16VK.OnCurve(curve.BN254); err == nil {
+		t.Fatal("key without Alpha is moved")
+	}
+}
+
+func TestMultiExpOnCurve(t *testing.T) {
+	for name, c := range curve.Curves {
+		points := make([]curve.G1, 20)
+		scalars := make([]*big.Int, len(points))
+		for i := range points {
+			points[i] = c.ScalarMultG1(c.G1Generator(), big.NewInt(int64(i+1)))
+			scalars[i] = new(big.Int).Sub(c.Order(), big.NewInt(int64(3*i)))
+		}
+		sum, err := multiExp(c, points, scalars, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := naiveMultiExp(c, points, scalars)
+		if !bytes.Equal(sum.Marshal(), expected.Marshal()) {
+			t.Fatalf("%s: wrong multi-exponentiation", name)
+		}
+	}
+}
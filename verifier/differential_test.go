@@ -0,0 +1,484 @@
+package verifier
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	mathrand "math/rand"
+	"testing"
+
+	google "github.com/ethereum/go-ethereum/crypto/bn256/google"
+	"github.com/shamatar/go-snarks/curve"
+)
+
+// Differential tests of the assembly optimized cloudflare bn256 against
+// the pure Go google bn256 and a big.Int reference. The reference does
+// affine G1 arithmetic on y^2 = x^3 + 3, and pairing checks are built from
+// points with known logarithms, so the expected result is sum(a[i] * b[i]) == 0
+
+// refG1 is an affine point of the reference, nil is the point at infinity
+type refG1 struct {
+	x, y *big.Int
+}
+
+func refUnmarshalG1(data []byte) (*refG1, bool) {
+	if len(data) != g1Size {
+		return nil, false
+	}
+	x := new(big.Int).SetBytes(data[:32])
+	y := new(big.Int).SetBytes(data[32:])
+	if x.Cmp(P) >= 0 || y.Cmp(P) >= 0 {
+		return nil, false
+	}
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, true
+	}
+	// y^2 == x^3 + 3
+	left := new(big.Int).Mul(y, y)
+	right := new(big.Int).Mul(x, x)
+	right.Mul(right, x).Add(right, big.NewInt(3))
+	if left.Sub(left, right).Mod(left, P).Sign() != 0 {
+		return nil, false
+	}
+	return &refG1{x, y}, true
+}
+
+func refMarshalG1(p *refG1) []byte {
+	data := make([]byte, g1Size)
+	if p != nil {
+		p.x.FillBytes(data[:32])
+		p.y.FillBytes(data[32:])
+	}
+	return data
+}
+
+func refAddG1(a, b *refG1) *refG1 {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	var lambda *big.Int
+	if a.x.Cmp(b.x) == 0 {
+		sum := new(big.Int).Add(a.y, b.y)
+		if sum.Mod(sum, P).Sign() == 0 {
+			return nil
+		}
+		// 3x^2 / 2y
+		lambda = new(big.Int).Mul(a.x, a.x)
+		lambda.Mul(lambda, big.NewInt(3))
+		lambda.Mul(lambda, new(big.Int).ModInverse(new(big.Int).Lsh(a.y, 1), P))
+	} else {
+		// (y2 - y1) / (x2 - x1)
+		dx := new(big.Int).Sub(b.x, a.x)
+		dx.Mod(dx, P)
+		lambda = new(big.Int).Sub(b.y, a.y)
+		lambda.Mul(lambda, dx.ModInverse(dx, P))
+	}
+	lambda.Mod(lambda, P)
+	x := new(big.Int).Mul(lambda, lambda)
+	x.Sub(x, a.x).Sub(x, b.x).Mod(x, P)
+	y := new(big.Int).Sub(a.x, x)
+	y.Mul(y, lambda).Sub(y, a.y).Mod(y, P)
+	return &refG1{x, y}
+}
+
+func refMulG1(p *refG1, k *big.Int) *refG1 {
+	var result *refG1
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		result = refAddG1(result, result)
+		if k.Bit(i) == 1 {
+			result = refAddG1(result, p)
+		}
+	}
+	return result
+}
+
+func refBase() *refG1 {
+	return &refG1{big.NewInt(1), big.NewInt(2)}
+}
+
+// googleAddG1 and googleMulG1 are AddG1 and MulG1 on google bn256
+func googleAddG1(data []byte) ([]byte, bool) {
+	if len(data) != 2*g1Size {
+		return nil, false
+	}
+	a, b := new(google.G1), new(google.G1)
+	if _, err := a.Unmarshal(data[:g1Size]); err != nil {
+		return nil, false
+	}
+	if _, err := b.Unmarshal(data[g1Size:]); err != nil {
+		return nil, false
+	}
+	return new(google.G1).Add(a, b).Marshal(), true
+}
+
+func googleMulG1(data []byte) ([]byte, bool) {
+	if len(data) != g1Size+32 {
+		return nil, false
+	}
+	p := new(google.G1)
+	if _, err := p.Unmarshal(data[:g1Size]); err != nil {
+		return nil, false
+	}
+	return new(google.G1).ScalarMult(p, new(big.Int).SetBytes(data[g1Size:])).Marshal(), true
+}
+
+func googlePairingCheck(data []byte) ([]byte, bool) {
+	if len(data)%ecPairingPairSize != 0 {
+		return nil, false
+	}
+	var a []*google.G1
+	var b []*google.G2
+	for i := 0; i < len(data); i += ecPairingPairSize {
+		g1, g2 := new(google.G1), new(google.G2)
+		if _, err := g1.Unmarshal(data[i : i+g1Size]); err != nil {
+			return nil, false
+		}
+		if _, err := g2.Unmarshal(data[i+g1Size : i+ecPairingPairSize]); err != nil {
+			return nil, false
+		}
+		a, b = append(a, g1), append(b, g2)
+	}
+	result := make([]byte, 32)
+	if google.PairingCheck(a, b) {
+		result[31] = 1
+	}
+	return result, true
+}
+
+func refAddG1Bytes(data []byte) ([]byte, bool) {
+	if len(data) != 2*g1Size {
+		return nil, false
+	}
+	a, ok := refUnmarshalG1(data[:g1Size])
+	if !ok {
+		return nil, false
+	}
+	b, ok := refUnmarshalG1(data[g1Size:])
+	if !ok {
+		return nil, false
+	}
+	return refMarshalG1(refAddG1(a, b)), true
+}
+
+func refMulG1Bytes(data []byte) ([]byte, bool) {
+	if len(data) != g1Size+32 {
+		return nil, false
+	}
+	p, ok := refUnmarshalG1(data[:g1Size])
+	if !ok {
+		return nil, false
+	}
+	return refMarshalG1(refMulG1(p, new(big.Int).SetBytes(data[g1Size:]))), true
+}
+
+// compareBackends fails if results of the implementations differ
+func compareBackends(t *testing.T, name string, data []byte, cloudflare func([]byte) ([]byte, error), others ...func([]byte) ([]byte, bool)) {
+	expected, err := cloudflare(data)
+	for i, other := range others {
+		result, ok := other(data)
+		if ok != (err == nil) {
+			t.Fatalf("%s: cloudflare error is %v, implementation %d accepts input: %v\ninput %x", name, err, i, ok, data)
+		}
+		if ok && !bytes.Equal(result, expected) {
+			t.Fatalf("%s: implementation %d result differs\ninput %x\ncloudflare %x\nother %x", name, i, data, expected, result)
+		}
+	}
+}
+
+// g1Inputs are points that are interesting for addition and multiplication
+func g1Inputs(random *mathrand.Rand) [][]byte {
+	k := new(big.Int).Rand(random, Order)
+	p := refMulG1(refBase(), k)
+	negP := &refG1{p.x, new(big.Int).Sub(P, p.y)}
+	return [][]byte{
+		refMarshalG1(nil),
+		refMarshalG1(refBase()),
+		refMarshalG1(p),
+		refMarshalG1(negP),
+		// not on the curve
+		append(make([]byte, 63), 1),
+		// x out of range
+		append(P.Bytes(), refMarshalG1(refBase())[32:]...),
+	}
+}
+
+func TestDifferentialG1(t *testing.T) {
+	random := mathrand.New(mathrand.NewSource(1))
+	scalars := []*big.Int{
+		big.NewInt(0), big.NewInt(1), big.NewInt(2),
+		new(big.Int).Sub(Order, big.NewInt(1)), Order, new(big.Int).Add(Order, big.NewInt(1)),
+		new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)),
+	}
+	for i := 0; i < 5; i++ {
+		points := g1Inputs(random)
+		for _, a := range points {
+			for _, b := range points {
+				compareBackends(t, "AddG1", append(append([]byte{}, a...), b...), AddG1, googleAddG1, refAddG1Bytes)
+			}
+			for _, k := range append(scalars, new(big.Int).Rand(random, Order)) {
+				scalar := make([]byte, 32)
+				k.FillBytes(scalar)
+				compareBackends(t, "MulG1", append(append([]byte{}, a...), scalar...), MulG1, googleMulG1, refMulG1Bytes)
+			}
+		}
+	}
+	compareBackends(t, "AddG1", make([]byte, 127), AddG1, googleAddG1, refAddG1Bytes)
+	compareBackends(t, "MulG1", make([]byte, 97), MulG1, googleMulG1, refMulG1Bytes)
+}
+
+// pairingInput makes ecPairing input from a[i] * G1 and b[i] * G2
+func pairingInput(a, b []*big.Int) []byte {
+	var data []byte
+	for i := range a {
+		data = append(data, new(G1).ScalarBaseMult(a[i]).Marshal()...)
+		data = append(data, new(G2).ScalarBaseMult(b[i]).Marshal()...)
+	}
+	return data
+}
+
+func TestDifferentialPairingCheck(t *testing.T) {
+	random := mathrand.New(mathrand.NewSource(2))
+	for pairs := 0; pairs <= 4; pairs++ {
+		for _, valid := range []bool{true, false} {
+			a := make([]*big.Int, pairs)
+			b := make([]*big.Int, pairs)
+			sum := big.NewInt(0)
+			for i := range a {
+				a[i] = new(big.Int).Rand(random, Order)
+				b[i] = new(big.Int).Rand(random, Order)
+				sum.Add(sum, new(big.Int).Mul(a[i], b[i]))
+			}
+			if pairs != 0 {
+				// the last pair makes the sum zero or one
+				last := pairs - 1
+				sum.Sub(sum, new(big.Int).Mul(a[last], b[last]))
+				b[last] = new(big.Int).Neg(sum)
+				if !valid {
+					b[last].Add(b[last], big.NewInt(1))
+				}
+				b[last].Mul(b[last], new(big.Int).ModInverse(a[last], Order)).Mod(b[last], Order)
+			}
+			data := pairingInput(a, b)
+			expected := make([]byte, 32)
+			if valid || pairs == 0 {
+				expected[31] = 1
+			}
+			result, err := PairingCheckBytes(data)
+			if err != nil || !bytes.Equal(result, expected) {
+				t.Fatalf("%d pairs: expected %x, got %x, %v", pairs, expected, result, err)
+			}
+			compareBackends(t, "PairingCheck", data, PairingCheckBytes, googlePairingCheck)
+		}
+	}
+
+	// the same checks with points at infinity and invalid points
+	data := pairingInput([]*big.Int{big.NewInt(0), big.NewInt(5)}, []*big.Int{big.NewInt(7), big.NewInt(0)})
+	compareBackends(t, "PairingCheck", data, PairingCheckBytes, googlePairingCheck)
+	data[ecPairingPairSize-1] ^= 1
+	compareBackends(t, "PairingCheck", data, PairingCheckBytes, googlePairingCheck)
+	compareBackends(t, "PairingCheck", data[1:], PairingCheckBytes, googlePairingCheck)
+}
+
+// compareVerifiers fails if the aggregate verifier, the split one and
+// the split one on google bn256 do not agree on a proof
+func compareVerifiers(t *testing.T, name string, vk *VerifyingKey, proof *Proof, witness Witness) {
+	aggregate := Verify(vk, proof, witness, WithStrategy(AggregateStrategy))
+	split := Verify(vk, proof, witness)
+	if aggregate != split {
+		t.Fatalf("%s: aggregate check gives %v, split gives %v", name, aggregate, split)
+	}
+	googleVK, err := vk.OnCurve(curve.BN254Google)
+	if err != nil {
+		t.Fatal(err)
+	}
+	googleProof, err := proof.OnCurve(curve.BN254Google)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if googleResult := VerifyOnCurve(googleVK, googleProof, witness); googleResult != split {
+		t.Fatalf("%s: google bn256 gives %v, cloudflare gives %v", name, googleResult, split)
+	}
+}
+
+func TestDifferentialVerification(t *testing.T) {
+	vk, proof, witness := zokratesExample(t)
+	compareVerifiers(t, "valid", vk, proof, witness)
+
+	corrupted := *proof
+	corrupted.H = corrupted.K
+	compareVerifiers(t, "corrupted H", vk, &corrupted, witness)
+	corrupted = *proof
+	corrupted.Bp = new(G1).Neg(corrupted.Bp)
+	compareVerifiers(t, "corrupted Bp", vk, &corrupted, witness)
+
+	wrongWitness := append(Witness{}, witness...)
+	wrongWitness[2] = big.NewInt(5)
+	compareVerifiers(t, "wrong witness", vk, proof, wrongWitness)
+
+	// batches agree with single proofs
+	vk, proofs, inputs := exampleBatch(t, 4)
+	proofs[2].A = new(G1).Neg(proofs[2].A)
+	err := BatchVerify(vk, proofs, inputs)
+	batchErr, ok := err.(*BatchError)
+	if !ok || len(batchErr.Invalid) != 1 || batchErr.Invalid[0] != 2 {
+		t.Fatalf("expected proof 2 to be invalid, got %v", err)
+	}
+	for i := range proofs {
+		compareVerifiers(t, "batch", vk, &proofs[i], inputs[i])
+	}
+}
+
+// compareParsers fails if a parsed proof differs from the one parsed by google bn256
+func compareParsers(t *testing.T, name string, data []byte) {
+	var proof Proof
+	err := proof.UnmarshalBinary(data)
+	googleProof, googleErr := UnmarshalCurveProof(curve.BN254Google, data)
+	if (err == nil) != (googleErr == nil) {
+		t.Fatalf("%s: cloudflare error is %v, google error is %v\ninput %x", name, err, googleErr, data)
+	}
+	if err != nil {
+		return
+	}
+	expected, _ := proof.MarshalBinary()
+	encoded, _ := googleProof.MarshalBinary()
+	if !bytes.Equal(expected, encoded) {
+		t.Fatalf("%s: parsed proofs differ\ninput %x", name, data)
+	}
+}
+
+func TestDifferentialParsers(t *testing.T) {
+	vk, proof, _ := zokratesExample(t)
+	data, _ := proof.MarshalBinary()
+	compareParsers(t, "valid", data)
+
+	// every text and JSON parser gives points that google bn256 accepts
+	text, _ := proof.MarshalText()
+	jsonData, _ := proof.MarshalJSON()
+	vkText, _ := vk.MarshalText()
+	for _, parse := range []func() (*Proof, error){
+		func() (*Proof, error) { p := new(Proof); return p, p.UnmarshalText(text) },
+		func() (*Proof, error) { p := new(Proof); return p, p.UnmarshalJSON(jsonData) },
+	} {
+		parsed, err := parse()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsedData, _ := parsed.MarshalBinary()
+		compareParsers(t, "text and JSON", parsedData)
+	}
+	parsedVK := new(VerifyingKey)
+	if err := parsedVK.UnmarshalText(vkText); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parsedVK.OnCurve(curve.BN254Google); err != nil {
+		t.Fatal(err)
+	}
+
+	// flipped bits make points invalid in the same way
+	random := mathrand.New(mathrand.NewSource(3))
+	for i := 0; i < 50; i++ {
+		corrupted := append([]byte{}, data...)
+		corrupted[random.Intn(len(corrupted))] ^= byte(1 << uint(random.Intn(8)))
+		compareParsers(t, "corrupted", corrupted)
+	}
+	// the whole word set to P or to zero
+	for _, offset := range []int{0, 32, 2 * g1Size, 2*g1Size + 96} {
+		corrupted := append([]byte{}, data...)
+		P.FillBytes(corrupted[offset : offset+32])
+		compareParsers(t, "coordinate P", corrupted)
+		for j := offset; j < offset+32; j++ {
+			corrupted[j] = 0
+		}
+		compareParsers(t, "zero coordinate", corrupted)
+	}
+}
+
+// the fuzz targets run the seeds with go test,
+// go test -fuzz FuzzDifferentialAddG1 ./verifier explores further
+
+func FuzzDifferentialAddG1(f *testing.F) {
+	random := mathrand.New(mathrand.NewSource(4))
+	points := g1Inputs(random)
+	for _, a := range points {
+		f.Add(append(append([]byte{}, a...), points[2]...))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		compareBackends(t, "AddG1", data, AddG1, googleAddG1, refAddG1Bytes)
+	})
+}
+
+func FuzzDifferentialMulG1(f *testing.F) {
+	f.Add([]byte{1}, []byte{2})
+	f.Add([]byte{}, Order.Bytes())
+	f.Add(Order.Bytes(), bytes.Repeat([]byte{0xff}, 32))
+	// a valid point is made from the first argument,
+	// so the fuzzer does not have to find points on the curve
+	f.Fuzz(func(t *testing.T, logarithm, scalar []byte) {
+		if len(scalar) > 32 {
+			scalar = scalar[:32]
+		}
+		data := refMarshalG1(refMulG1(refBase(), new(big.Int).SetBytes(logarithm)))
+		data = append(data, make([]byte, 32-len(scalar))...)
+		data = append(data, scalar...)
+		compareBackends(t, "MulG1", data, MulG1, googleMulG1, refMulG1Bytes)
+	})
+}
+
+func FuzzDifferentialPairingCheck(f *testing.F) {
+	f.Add([]byte{1, 2, 3, 4})
+	f.Add([]byte{0, 0, 0, 0, 1, 1})
+	// pairs of logarithms of G1 and G2 points
+	f.Fuzz(func(t *testing.T, logarithms []byte) {
+		if len(logarithms) > 8 {
+			logarithms = logarithms[:8]
+		}
+		a := make([]*big.Int, len(logarithms)/2)
+		b := make([]*big.Int, len(logarithms)/2)
+		sum := big.NewInt(0)
+		for i := range a {
+			a[i] = big.NewInt(int64(logarithms[2*i]))
+			b[i] = big.NewInt(int64(logarithms[2*i+1]))
+			sum.Add(sum, new(big.Int).Mul(a[i], b[i]))
+		}
+		data := pairingInput(a, b)
+		expected := make([]byte, 32)
+		if sum.Sign() == 0 {
+			expected[31] = 1
+		}
+		result, err := PairingCheckBytes(data)
+		if err != nil || !bytes.Equal(result, expected) {
+			t.Fatalf("expected %x, got %x, %v", expected, result, err)
+		}
+		compareBackends(t, "PairingCheck", data, PairingCheckBytes, googlePairingCheck)
+	})
+}
+
+func FuzzDifferentialProofParser(f *testing.F) {
+	_, proof, _ := zokratesExample(f)
+	data, _ := proof.MarshalBinary()
+	f.Add(data)
+	f.Add(make([]byte, len(data)))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		compareParsers(t, "fuzz", data)
+	})
+}
+
+func TestReferenceG1(t *testing.T) {
+	// the reference agrees with the curve on random multiples
+	for i := 0; i < 3; i++ {
+		k, err := rand.Int(rand.Reader, Order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := new(G1).ScalarBaseMult(k).Marshal()
+		if !bytes.Equal(refMarshalG1(refMulG1(refBase(), k)), expected) {
+			t.Fatal("reference multiplication is wrong")
+		}
+	}
+	if refMulG1(refBase(), Order) != nil {
+		t.Fatal("Order * G1 is not infinity")
+	}
+}
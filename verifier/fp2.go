@@ -0,0 +1,50 @@
+package verifier
+
+import "math/big"
+
+// Arithmetic of Fp2 over big.Int for the few places that need coordinates
+// outside of bn256, it's slow and is not used in pairing checks
+
+// fp2 is an element c0 + c1*i of the quadratic extension, i^2 = -1
+type fp2 [2]*big.Int
+
+func (a fp2) isZero() bool {
+	return a[0].Sign() == 0 && a[1].Sign() == 0
+}
+
+func (a fp2) equal(b fp2) bool {
+	return a[0].Cmp(b[0]) == 0 && a[1].Cmp(b[1]) == 0
+}
+
+func (a fp2) add(b fp2) fp2 {
+	c0 := new(big.Int).Add(a[0], b[0])
+	c1 := new(big.Int).Add(a[1], b[1])
+	return fp2{c0.Mod(c0, P), c1.Mod(c1, P)}
+}
+
+func (a fp2) mul(b fp2) fp2 {
+	c0 := new(big.Int).Mul(a[0], b[0])
+	c0.Sub(c0, new(big.Int).Mul(a[1], b[1]))
+	c1 := new(big.Int).Mul(a[0], b[1])
+	c1.Add(c1, new(big.Int).Mul(a[1], b[0]))
+	return fp2{c0.Mod(c0, P), c1.Mod(c1, P)}
+}
+
+// inverse is (c0 - c1*i) / (c0^2 + c1^2), the element should not be zero
+func (a fp2) inverse() fp2 {
+	norm := new(big.Int).Mul(a[0], a[0])
+	norm.Add(norm, new(big.Int).Mul(a[1], a[1]))
+	norm.ModInverse(norm.Mod(norm, P), P)
+	c0 := new(big.Int).Mul(a[0], norm)
+	c1 := new(big.Int).Mul(a[1], norm)
+	c1.Neg(c1)
+	return fp2{c0.Mod(c0, P), c1.Mod(c1, P)}
+}
+
+// twistB is 3/(9+i), the twisted curve G2 lives on is y^2 = x^3 + twistB
+var twistB = fp2{big.NewInt(3), big.NewInt(0)}.mul(fp2{big.NewInt(9), big.NewInt(1)}.inverse())
+
+// onTwist checks the curve equation for affine x, y
+func onTwist(x, y fp2) bool {
+	return y.mul(y).equal(x.mul(x).mul(x).add(twistB))
+}
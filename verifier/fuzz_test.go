@@ -0,0 +1,208 @@
+package verifier
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// Fuzz targets of the parsers. go test runs the seeds only, to explore run
+// go test -run XXX -fuzz FuzzParseProofFromString ./verifier
+// Parsers must never panic, and whatever they accept must survive a round trip
+
+// addSeedFiles adds contents of the files to the corpus
+func addSeedFiles(f *testing.F, filenames ...string) [][]byte {
+	var seeds [][]byte
+	for _, filename := range filenames {
+		content, err := ioutil.ReadFile(filename)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(content)
+		seeds = append(seeds, content)
+	}
+	return seeds
+}
+
+// addTruncated adds prefixes of the seed cut in the middle of every line
+func addTruncated(f *testing.F, seed []byte) {
+	for i := bytes.IndexByte(seed, '\n'); i > 0; {
+		f.Add(seed[:i/2+1])
+		next := bytes.IndexByte(seed[i+1:], '\n')
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+}
+
+func FuzzParseProofFromString(f *testing.F) {
+	for _, seed := range addSeedFiles(f, "../proof.txt", "proof.txt") {
+		addTruncated(f, seed)
+		f.Add(bytes.Replace(seed, []byte(" 0 "), []byte(" 1 "), 1))
+		f.Add(append(append([]byte{}, seed...), " 0 1 2"...))
+	}
+	f.Add([]byte("0"))
+	f.Add([]byte(""))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		proof, err := ParseProofFromString(string(data))
+		if err != nil {
+			return
+		}
+		text, err := proof.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := ParseProofFromString(string(text))
+		if err != nil {
+			t.Fatalf("written proof is not parsed: %v", err)
+		}
+		expected, _ := proof.MarshalBinary()
+		encoded, _ := parsed.MarshalBinary()
+		if !bytes.Equal(expected, encoded) {
+			t.Fatal("proof has changed after a round trip")
+		}
+	})
+}
+
+func FuzzLibsnarkVerifyingKey(f *testing.F) {
+	for _, seed := range addSeedFiles(f, "../vk_key.txt", "verificationKey.txt") {
+		addTruncated(f, seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		vk := new(LibsnarkVerifyingKey)
+		if err := vk.ParseFromReader(bytes.NewReader(data)); err != nil {
+			return
+		}
+		text, err := vk.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed := new(LibsnarkVerifyingKey)
+		if err := parsed.UnmarshalText(text); err != nil {
+			t.Fatalf("written key is not parsed: %v", err)
+		}
+		expected, _ := vk.MarshalBinary()
+		encoded, _ := parsed.MarshalBinary()
+		if !bytes.Equal(expected, encoded) {
+			t.Fatal("key has changed after a round trip")
+		}
+		// a sparse key always expands into a valid one
+		if _, err := vk.ToVerifyingKey(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzSparseVector(f *testing.F) {
+	vk := new(LibsnarkVerifyingKey)
+	if err := vk.ParseFromFile("../vk_key.txt"); err != nil {
+		f.Fatal(err)
+	}
+	var seed bytes.Buffer
+	vk.IC.writeText(&seed)
+	f.Add(seed.Bytes())
+	addTruncated(f, seed.Bytes())
+	f.Add([]byte("1 0 1\n3\n2\n0\n2\n2\n1 0 1\n1 0 1\n"))
+	f.Add([]byte("1 0 1\n3\n2\n2\n0\n2\n1 0 1\n1 0 1\n"))
+	f.Add([]byte("1 0 1\n18446744073709551615\n0\n0\n"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		sv := new(SparseVector)
+		if err := sv.ParseFromReader(bytes.NewReader(data)); err != nil {
+			return
+		}
+		dense := sv.dense()
+		if uint64(len(dense)) != sv.domainSize+1 {
+			t.Fatalf("%d points for domain of size %d", len(dense), sv.domainSize)
+		}
+		var text bytes.Buffer
+		sv.writeText(&text)
+		parsed := new(SparseVector)
+		if err := parsed.ParseFromReader(&text); err != nil {
+			t.Fatalf("written vector is not parsed: %v", err)
+		}
+	})
+}
+
+func FuzzNewG1FromStrings(f *testing.F) {
+	f.Add("1", "2", false)
+	f.Add("0x1", "0x2", true)
+	f.Add("0", "0", false)
+	f.Add("-1", "2", false)
+	f.Add(P.String(), "2", false)
+	f.Add("0x", "0x2", true)
+	f.Fuzz(func(t *testing.T, x, y string, hex bool) {
+		radix := 10
+		if hex {
+			radix = 16
+		}
+		p, err := NewG1FromStrings(x, y, radix)
+		if err != nil {
+			return
+		}
+		if _, err := UnmarshalG1(p.Marshal()); err != nil {
+			t.Fatalf("accepted point is invalid: %v", err)
+		}
+	})
+}
+
+func FuzzNewG2FromStrings(f *testing.F) {
+	g2 := g2ToJSON(GetG2Base())
+	f.Add(g2[0][0], g2[0][1], g2[1][0], g2[1][1], true)
+	f.Add("0", "0", "0", "0", false)
+	f.Add("1", "2", "3", "4", false)
+	f.Add(strings.Repeat("9", 80), "0", "0", "0", false)
+	f.Fuzz(func(t *testing.T, a0, a1, b0, b1 string, hex bool) {
+		radix := 10
+		if hex {
+			radix = 16
+		}
+		p, err := NewG2FromStrings([2]string{a0, a1}, [2]string{b0, b1}, radix)
+		if err != nil {
+			return
+		}
+		if _, err := UnmarshalG2(p.Marshal()); err != nil {
+			t.Fatalf("accepted point is invalid: %v", err)
+		}
+	})
+}
+
+func FuzzAddG1(f *testing.F) {
+	base := GetG1Base().Marshal()
+	f.Add(append(append([]byte{}, base...), base...))
+	f.Add(append(make([]byte, 64), base...))
+	f.Add(make([]byte, 128))
+	f.Add(make([]byte, 127))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		result, err := AddG1(data)
+		if err != nil {
+			return
+		}
+		// addition is commutative
+		swapped, err := AddG1(append(append([]byte{}, data[64:]...), data[:64]...))
+		if err != nil || !bytes.Equal(result, swapped) {
+			t.Fatalf("a + b != b + a: %v", err)
+		}
+		if _, err := UnmarshalG1(result); err != nil {
+			t.Fatalf("result is invalid: %v", err)
+		}
+	})
+}
+
+func FuzzMulG1(f *testing.F) {
+	base := GetG1Base().Marshal()
+	f.Add(append(append([]byte{}, base...), make([]byte, 32)...))
+	f.Add(append(append([]byte{}, base...), bytes.Repeat([]byte{0xff}, 32)...))
+	f.Add(append(append([]byte{}, base...), Order.Bytes()...))
+	f.Add(make([]byte, 95))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		result, err := MulG1(data)
+		if err != nil {
+			return
+		}
+		if _, err := UnmarshalG1(result); err != nil {
+			t.Fatalf("result is invalid: %v", err)
+		}
+	})
+}
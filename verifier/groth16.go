@@ -0,0 +1,113 @@
+package verifier
+
+import (
+	"bytes"
+	"errors"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	"github.com/shamatar/go-snarks/curve"
+)
+
+// verify a Groth16 snark
+// 1 pairing equation, 3 group elements of a proof
+
+// Groth16VerifyingKey lists the points of the Groth16 key.
+// libsnark only publishes e(Alpha, Beta) as AlphaBeta, so either Alpha and Beta
+// or AlphaBeta should be set
+type Groth16VerifyingKey struct {
+	Alpha     *G1
+	Beta      *G2
+	AlphaBeta *GT
+	Gamma     *G2
+	Delta     *G2
+	IC        []*G1 // set of G1 point to multiply a witness on
+}
+
+// Groth16Proof is a proof in Groth16 proving system
+type Groth16Proof struct {
+	A *G1
+	B *G2
+	C *G1
+}
+
+// ErrGroth16Check is returned if Groth16 pairing equation has failed
+var ErrGroth16Check = errors.New("Groth16 pairing equation has failed")
+
+// VerifyGroth16 checks a Groth16 proof for a set of public inputs.
+// Returns nil if proof is valid. Of the options only WithWitnessReduction
+// makes sense here
+func VerifyGroth16(vk *Groth16VerifyingKey, proof *Groth16Proof, inputs Witness, opts ...Option) error {
+	o := newOptions(opts)
+	if err := vk.validate(); err != nil {
+		return err
+	}
+	if err := proof.validate(); err != nil {
+		return err
+	}
+	inputs, err := checkWitness(inputs, len(vk.IC), o.reduceWitness)
+	if err != nil {
+		return err
+	}
+	return groth16Verification(inputs, proof, vk)
+}
+
+// groth16Verification checks
+// e(proof.A, proof.B) == e(vk.Alpha, vk.Beta) * e(witnessAccumulator, vk.Gamma) * e(proof.C, vk.Delta)
+func groth16Verification(witness Witness, proof *Groth16Proof, vk *Groth16VerifyingKey) error {
+	if vk.Alpha != nil && vk.Beta != nil {
+		return curveGroth16Verification(witness, proof.onBN254(), vk.onBN254(), 1)
+	}
+
+	// e(vk.Alpha, vk.Beta) is only known in GT, so the Miller loops are finalized here
+	witnessAccumulator, err := accumulateWitness(curve.BN254, curveG1s(vk.IC), witness, 1)
+	if err != nil {
+		return err
+	}
+	pair := bn256.Miller(new(G1).Neg(proof.A), proof.B)
+	pair = pair.Add(pair, bn256.Miller(witnessAccumulator.(*G1), vk.Gamma))
+	pair = pair.Add(pair, bn256.Miller(proof.C, vk.Delta))
+	pair.Finalize()
+	pair = pair.Add(pair, vk.AlphaBeta)
+	if !bytes.Equal(pair.Marshal(), IdentityBytes) {
+		return ErrGroth16Check
+	}
+	return nil
+}
+
+// curveGroth16Verification checks the equation of groth16Verification on the curve of the key
+func curveGroth16Verification(witness Witness, proof *CurveGroth16Proof, vk *CurveGroth16VerifyingKey, workers int) error {
+	c := vk.Curve
+	witnessAccumulator, err := accumulateWitness(c, vk.IC, witness, workers)
+	if err != nil {
+		return err
+	}
+	success := c.PairingCheck(
+		[]curve.G1{c.NegG1(proof.A), vk.Alpha, witnessAccumulator, proof.C},
+		[]curve.G2{proof.B, vk.Beta, vk.Gamma, vk.Delta})
+	if !success {
+		return ErrGroth16Check
+	}
+	return nil
+}
+
+func (vk *Groth16VerifyingKey) validate() error {
+	if vk == nil || vk.Gamma == nil || vk.Delta == nil || len(vk.IC) == 0 {
+		return ErrInvalidVerifyingKey
+	}
+	if (vk.Alpha == nil || vk.Beta == nil) && vk.AlphaBeta == nil {
+		return ErrInvalidVerifyingKey
+	}
+	for _, p := range vk.IC {
+		if p == nil {
+			return ErrInvalidVerifyingKey
+		}
+	}
+	return nil
+}
+
+func (proof *Groth16Proof) validate() error {
+	if proof == nil || proof.A == nil || proof.B == nil || proof.C == nil {
+		return ErrInvalidProof
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package verifier
+
+import (
+	"io"
+	"os"
+)
+
+// libsnark r1cs_gg_ppzksnark text format
+// verifying key is
+// alpha_g1_beta_g2 (GT, 12 numbers)
+// gamma_g2
+// delta_g2
+// gamma_ABC_g1 (accumulation vector)
+// proof is
+// g_A
+// g_B
+// g_C
+
+// ParseFromFile parses libsnark r1cs_gg_ppzksnark verifying key
+func (vk *Groth16VerifyingKey) ParseFromFile(filename string) error {
+	r, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return vk.ParseFromReader(r)
+}
+
+// ParseFromReader parses libsnark r1cs_gg_ppzksnark verifying key
+func (vk *Groth16VerifyingKey) ParseFromReader(r io.Reader) error {
+	t := newTokenizer(r)
+	AlphaBeta, err := t.readGT()
+	if err != nil {
+		return err
+	}
+	Gamma, err := t.readG2()
+	if err != nil {
+		return err
+	}
+	Delta, err := t.readG2()
+	if err != nil {
+		return err
+	}
+	ic := new(SparseVector)
+	err = ic.parse(t)
+	if err != nil {
+		return err
+	}
+
+	vk.Alpha = nil
+	vk.Beta = nil
+	vk.AlphaBeta = AlphaBeta
+	vk.Gamma = Gamma
+	vk.Delta = Delta
+	vk.IC = ic.dense()
+	return nil
+}
+
+// ParseFromFile parses libsnark r1cs_gg_ppzksnark proof
+func (proof *Groth16Proof) ParseFromFile(filename string) error {
+	r, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return proof.ParseFromReader(r)
+}
+
+// ParseFromReader parses libsnark r1cs_gg_ppzksnark proof
+func (proof *Groth16Proof) ParseFromReader(r io.Reader) error {
+	t := newTokenizer(r)
+	A, err := t.readG1()
+	if err != nil {
+		return err
+	}
+	B, err := t.readG2()
+	if err != nil {
+		return err
+	}
+	C, err := t.readG1()
+	if err != nil {
+		return err
+	}
+
+	proof.A = A
+	proof.B = B
+	proof.C = C
+	return nil
+}
@@ -0,0 +1,244 @@
+package verifier
+
+import (
+	"bufio"
+	"crypto/rand"
+	"math/big"
+	"strings"
+	"testing"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+func randomScalar(t testing.TB) *big.Int {
+	k, err := rand.Int(rand.Reader, Order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+// groth16Example makes a key from random toxic waste and simulates a valid
+// proof for the witness using it, so there is no need for a circuit
+func groth16Example(t testing.TB, witness Witness) (*Groth16VerifyingKey, *Groth16Proof) {
+	alpha, beta, gamma, delta := randomScalar(t), randomScalar(t), randomScalar(t), randomScalar(t)
+	vk := &Groth16VerifyingKey{
+		Alpha: new(G1).ScalarBaseMult(alpha),
+		Beta:  new(G2).ScalarBaseMult(beta),
+		Gamma: new(G2).ScalarBaseMult(gamma),
+		Delta: new(G2).ScalarBaseMult(delta),
+		IC:    make([]*G1, len(witness)+1),
+	}
+	vk.AlphaBeta = bn256.Pair(vk.Alpha, vk.Beta)
+
+	// logarithm of the witness accumulator
+	accumulated := big.NewInt(0)
+	for i := range vk.IC {
+		u := randomScalar(t)
+		vk.IC[i] = new(G1).ScalarBaseMult(u)
+		if i != 0 {
+			u.Mul(u, witness[i-1])
+		}
+		accumulated.Add(accumulated, u)
+	}
+
+	// a * b = alpha * beta + accumulated * gamma + c * delta
+	a, b := randomScalar(t), randomScalar(t)
+	c := new(big.Int).Mul(a, b)
+	c.Sub(c, new(big.Int).Mul(alpha, beta))
+	c.Sub(c, new(big.Int).Mul(accumulated, gamma))
+	c.Mul(c, new(big.Int).ModInverse(delta, Order))
+	c.Mod(c, Order)
+
+	proof := &Groth16Proof{
+		A: new(G1).ScalarBaseMult(a),
+		B: new(G2).ScalarBaseMult(b),
+		C: new(G1).ScalarBaseMult(c),
+	}
+	return vk, proof
+}
+
+// libsnark text representation of the points
+func words(data []byte) []string {
+	result := make([]string, len(data)/32)
+	for i := range result {
+		result[i] = new(big.Int).SetBytes(data[i*32 : (i+1)*32]).String()
+	}
+	return result
+}
+
+func libsnarkG1(p *G1) string {
+	w := words(p.Marshal())
+	return "0 " + w[0] + " " + w[1]
+}
+
+func libsnarkG2(p *G2) string {
+	w := words(p.Marshal())
+	return "0 " + w[1] + " " + w[0] + " " + w[3] + " " + w[2]
+}
+
+func libsnarkGT(p *GT) string {
+	w := words(p.Marshal())
+	for i, j := 0, len(w)-1; i < j; i, j = i+1, j-1 {
+		w[i], w[j] = w[j], w[i]
+	}
+	return strings.Join(w, " ")
+}
+
+func TestGroth16Verification(t *testing.T) {
+	witness := Witness{big.NewInt(1), big.NewInt(2), big.NewInt(3)}
+	vk, proof := groth16Example(t, witness)
+	if err := VerifyGroth16(vk, proof, witness); err != nil {
+		t.Fatal(err)
+	}
+
+	// libsnark keys have AlphaBeta only
+	alpha, beta := vk.Alpha, vk.Beta
+	vk.Alpha, vk.Beta = nil, nil
+	if err := VerifyGroth16(vk, proof, witness); err != nil {
+		t.Fatal(err)
+	}
+	vk.Alpha, vk.Beta = alpha, beta
+
+	witness[2] = big.NewInt(4)
+	if err := VerifyGroth16(vk, proof, witness); err != ErrGroth16Check {
+		t.Fatalf("expected %v, got %v", ErrGroth16Check, err)
+	}
+	vk.Alpha, vk.Beta = nil, nil
+	if err := VerifyGroth16(vk, proof, witness); err != ErrGroth16Check {
+		t.Fatalf("expected %v, got %v", ErrGroth16Check, err)
+	}
+}
+
+func TestGroth16LibsnarkParsing(t *testing.T) {
+	witness := Witness{big.NewInt(5), big.NewInt(7)}
+	vk, proof := groth16Example(t, witness)
+
+	lines := []string{
+		libsnarkGT(vk.AlphaBeta),
+		libsnarkG2(vk.Gamma),
+		libsnarkG2(vk.Delta),
+		libsnarkG1(vk.IC[0]),
+		"2", "2", "0", "1", "2",
+		libsnarkG1(vk.IC[1]),
+		libsnarkG1(vk.IC[2]),
+	}
+	parsedVK := new(Groth16VerifyingKey)
+	err := parsedVK.ParseFromReader(bufio.NewReader(strings.NewReader(strings.Join(lines, "\n") + "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines = []string{libsnarkG1(proof.A), libsnarkG2(proof.B), libsnarkG1(proof.C)}
+	parsedProof := new(Groth16Proof)
+	err = parsedProof.ParseFromReader(bufio.NewReader(strings.NewReader(strings.Join(lines, "\n") + "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := VerifyGroth16(parsedVK, parsedProof, witness); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Fp12 of libff alt_bn128 written independently of bn256: Fp2 = Fp[u]/(u^2 + 1),
+// Fp6 = Fp2[v]/(v^3 - (9 + u)), Fp12 = Fp6[w]/(w^2 - v). Coefficients are
+// listed in the order libsnark prints them, c0 before c1 on every level
+type libffFp2 [2]*big.Int
+type libffFp6 [3]libffFp2
+type libffFp12 [2]libffFp6
+
+func (a libffFp2) add(b libffFp2) libffFp2 {
+	return libffFp2{
+		new(big.Int).Mod(new(big.Int).Add(a[0], b[0]), P),
+		new(big.Int).Mod(new(big.Int).Add(a[1], b[1]), P),
+	}
+}
+
+func (a libffFp2) mul(b libffFp2) libffFp2 {
+	c0 := new(big.Int).Sub(new(big.Int).Mul(a[0], b[0]), new(big.Int).Mul(a[1], b[1]))
+	c1 := new(big.Int).Add(new(big.Int).Mul(a[0], b[1]), new(big.Int).Mul(a[1], b[0]))
+	return libffFp2{c0.Mod(c0, P), c1.Mod(c1, P)}
+}
+
+// libffXi is the non-residue 9 + u of Fp6
+var libffXi = libffFp2{big.NewInt(9), big.NewInt(1)}
+
+func (a libffFp6) add(b libffFp6) libffFp6 {
+	return libffFp6{a[0].add(b[0]), a[1].add(b[1]), a[2].add(b[2])}
+}
+
+func (a libffFp6) mul(b libffFp6) libffFp6 {
+	return libffFp6{
+		a[0].mul(b[0]).add(libffXi.mul(a[1].mul(b[2]).add(a[2].mul(b[1])))),
+		a[0].mul(b[1]).add(a[1].mul(b[0])).add(libffXi.mul(a[2].mul(b[2]))),
+		a[0].mul(b[2]).add(a[1].mul(b[1])).add(a[2].mul(b[0])),
+	}
+}
+
+// mulByV multiplies by v, the non-residue of Fp12
+func (a libffFp6) mulByV() libffFp6 {
+	return libffFp6{libffXi.mul(a[2]), a[0], a[1]}
+}
+
+func (a libffFp12) mul(b libffFp12) libffFp12 {
+	return libffFp12{
+		a[0].mul(b[0]).add(a[1].mul(b[1]).mulByV()),
+		a[0].mul(b[1]).add(a[1].mul(b[0])),
+	}
+}
+
+func (a libffFp12) String() string {
+	var w []string
+	for _, c6 := range a {
+		for _, c2 := range c6 {
+			w = append(w, c2[0].String(), c2[1].String())
+		}
+	}
+	return strings.Join(w, " ")
+}
+
+func randomLibffFp12(t *testing.T) libffFp12 {
+	var a libffFp12
+	for i := range a {
+		for j := range a[i] {
+			for k := range a[i][j] {
+				c, err := rand.Int(rand.Reader, P)
+				if err != nil {
+					t.Fatal(err)
+				}
+				a[i][j][k] = c
+			}
+		}
+	}
+	return a
+}
+
+// TestLibsnarkGTTower checks the coefficient order of parsed GT elements
+// against the libff tower: the parser has to turn products of libff into
+// products of bn256, which fails for a wrong tower or order
+func TestLibsnarkGTTower(t *testing.T) {
+	parse := func(text string) *GT {
+		gt, err := newTokenizer(strings.NewReader(text)).readGT()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return gt
+	}
+	one := "1 0 0 0 0 0 0 0 0 0 0 0"
+	if parse(one).String() != GTIdentity.String() {
+		t.Fatal("libsnark one is not parsed as one")
+	}
+	for i := 0; i < 10; i++ {
+		a, b := randomLibffFp12(t), randomLibffFp12(t)
+		expected := new(GT).Add(parse(a.String()), parse(b.String()))
+		if product := parse(a.mul(b).String()); product.String() != expected.String() {
+			t.Fatalf("product of %v and %v is parsed as %v, expected %v", a, b, product, expected)
+		}
+	}
+	// the pairing of libsnark keys is the pairing of bn256
+	alpha, beta := new(G1).ScalarBaseMult(randomScalar(t)), new(G2).ScalarBaseMult(randomScalar(t))
+	if parse(libsnarkGT(bn256.Pair(alpha, beta))).String() != bn256.Pair(alpha, beta).String() {
+		t.Fatal("pairing is parsed differently")
+	}
+}
@@ -4,169 +4,276 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
+	"math/big"
 	"os"
 	"strconv"
+	"strings"
+	"unicode"
 )
 
-func skipSpaces(r *bufio.Reader) {
-	for {
-		rune, _, err := r.ReadRune()
-		if err != nil {
-			if err.Error() == "EOF" {
-				return
-			}
-		}
-		c := string(rune)
-		if c != " " {
-			r.UnreadRune()
-			return
-		}
-	}
+// libsnark text format is a sequence of whitespace separated tokens
+// G1 point is "flag x y", G2 point is "flag x.c0 x.c1 y.c0 y.c1",
+// where flag is 1 for the point at infinity and 0 otherwise.
+// Coordinates are printed even for the point at infinity
+
+// ParseError reports malformed input with position of the offending token
+type ParseError struct {
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// maxTokenLength is enough for any number of the format,
+// so a long garbage token is rejected before it is parsed
+const maxTokenLength = 256
+
+// tokenizer splits the input into whitespace separated tokens
+// and remembers where the last one has started
+type tokenizer struct {
+	r           *bufio.Reader
+	line        int
+	column      int
+	tokenLine   int
+	tokenColumn int
 }
 
-func consumeNewLine(r *bufio.Reader) {
-	rune, _, err := r.ReadRune()
+func newTokenizer(r io.Reader) *tokenizer {
+	return &tokenizer{r: bufio.NewReader(r), line: 1}
+}
+
+func (t *tokenizer) readRune() (rune, error) {
+	c, _, err := t.r.ReadRune()
 	if err != nil {
-		if err.Error() == "EOF" {
-			return
-		}
+		return 0, err
 	}
-	c := string(rune)
-	if c != "\n" {
-		r.UnreadRune()
-		return
+	if c == '\n' {
+		t.line++
+		t.column = 0
+	} else {
+		t.column++
 	}
+	return c, nil
 }
 
-func ReadInt(r *bufio.Reader) (uint64, error) {
-	x := ""
+// errorf makes a ParseError at the start of the last token
+func (t *tokenizer) errorf(format string, args ...interface{}) error {
+	return &ParseError{Line: t.tokenLine, Column: t.tokenColumn, Err: fmt.Errorf(format, args...)}
+}
+
+// next returns the next token, io.ErrUnexpectedEOF is reported if there is none
+func (t *tokenizer) next() (string, error) {
+	var c rune
+	var err error
 	for {
-		rune, _, err := r.ReadRune()
+		c, err = t.readRune()
+		if err == io.EOF {
+			t.tokenLine, t.tokenColumn = t.line, t.column+1
+			return "", &ParseError{Line: t.tokenLine, Column: t.tokenColumn, Err: io.ErrUnexpectedEOF}
+		}
 		if err != nil {
-			if err.Error() == "EOF" {
-				break
-			}
-			return 0, err
+			return "", err
 		}
-		c := string(rune)
-		if c != " " && c != "\n" {
-			x = x + c
-		} else {
-			r.UnreadRune()
+		if !unicode.IsSpace(c) {
 			break
 		}
 	}
-	i, err := strconv.ParseUint(x, 10, 64)
-	if err != nil {
-		return 0, err
-	}
-	return i, nil
-}
+	t.tokenLine, t.tokenColumn = t.line, t.column
 
-func ReadBigIntAsString(r *bufio.Reader) (string, error) {
-	x := ""
+	var token strings.Builder
 	for {
-		rune, _, err := r.ReadRune()
+		if token.Len() >= maxTokenLength {
+			return "", t.errorf("token is longer than %d bytes", maxTokenLength)
+		}
+		token.WriteRune(c)
+		c, err = t.readRune()
+		if err == io.EOF {
+			return token.String(), nil
+		}
 		if err != nil {
-			if err.Error() == "EOF" {
-				break
-			}
 			return "", err
 		}
-		c := string(rune)
-		if c != " " && c != "\n" {
-			x = x + c
-		} else {
-			r.UnreadRune()
-			break
+		if unicode.IsSpace(c) {
+			return token.String(), nil
 		}
 	}
-	return x, nil
 }
 
-func ReadG1(r *bufio.Reader) (*G1, error) {
-	rune, _, err := r.ReadRune()
+func (t *tokenizer) readUint() (uint64, error) {
+	token, err := t.next()
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	str := string(rune)
-	if str == "1" {
-		return new(G1), nil
-	} else if str != "0" {
-		return nil, errors.New("Invalid encoding format")
+	i, err := strconv.ParseUint(token, 10, 64)
+	if err != nil {
+		return 0, t.errorf("invalid integer %q", token)
 	}
+	return i, nil
+}
 
-	skipSpaces(r)
-	xCoord, err := ReadBigIntAsString(r)
+func (t *tokenizer) readBigInt() (*big.Int, error) {
+	token, err := t.next()
 	if err != nil {
 		return nil, err
 	}
-	skipSpaces(r)
-	yCoord, err := ReadBigIntAsString(r)
+	n, err := base10bi(token)
 	if err != nil {
-		return nil, err
+		return nil, t.errorf("invalid number %q", token)
 	}
-	consumeNewLine(r)
+	return n, nil
+}
+
+// readInfinityFlag reads "0" or "1" that starts every point
+func (t *tokenizer) readInfinityFlag() (bool, int, int, error) {
+	token, err := t.next()
 	if err != nil {
-		return nil, err
+		return false, 0, 0, err
 	}
-	fmt.Println("X = " + xCoord)
-	fmt.Println("Y = " + yCoord)
-	newPoint, err := NewG1FromStrings(xCoord, yCoord, 10)
-	if err != nil {
-		return nil, err
+	switch token {
+	case "0":
+		return false, t.tokenLine, t.tokenColumn, nil
+	case "1":
+		return true, t.tokenLine, t.tokenColumn, nil
 	}
-	return newPoint, nil
+	return false, 0, 0, t.errorf("invalid infinity flag %q", token)
 }
 
-func ReadG2(r *bufio.Reader) (*G2, error) {
-	rune, _, err := r.ReadRune()
+func (t *tokenizer) readG1() (*G1, error) {
+	infinity, line, column, err := t.readInfinityFlag()
 	if err != nil {
 		return nil, err
 	}
-	str := string(rune)
-	if str == "1" {
-		return new(G2), nil
-	} else if str != "0" {
-		return nil, errors.New("Invalid encoding format")
-	}
-	skipSpaces(r)
-	a, err := ReadBigIntAsString(r)
+	x, err := t.readBigInt()
 	if err != nil {
 		return nil, err
 	}
-	skipSpaces(r)
-	b, err := ReadBigIntAsString(r)
+	y, err := t.readBigInt()
 	if err != nil {
 		return nil, err
 	}
-	skipSpaces(r)
-	c, err := ReadBigIntAsString(r)
+	if infinity {
+		return new(G1).ScalarBaseMult(big.NewInt(0)), nil
+	}
+	point, err := NewG1(x, y)
 	if err != nil {
-		return nil, err
+		return nil, &ParseError{Line: line, Column: column, Err: err}
 	}
-	skipSpaces(r)
-	d, err := ReadBigIntAsString(r)
+	return point, nil
+}
+
+func (t *tokenizer) readG2() (*G2, error) {
+	infinity, line, column, err := t.readInfinityFlag()
 	if err != nil {
 		return nil, err
 	}
-	consumeNewLine(r)
-	fmt.Println("A = " + a)
-	fmt.Println("B = " + b)
-	fmt.Println("C = " + c)
-	fmt.Println("D = " + d)
-	newPoint, err := NewG2FromStrings([2]string{a, b}, [2]string{c, d}, 10)
-	// if err != nil {
-	// 	return nil, err
-	// }
-	return newPoint, nil
+	coordinates := make([]*big.Int, 4)
+	for i := range coordinates {
+		coordinates[i], err = t.readBigInt()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if infinity {
+		return new(G2).ScalarBaseMult(big.NewInt(0)), nil
+	}
+	// libsnark prints Fp2 elements as c0 c1, while bn256 expects c1 c0
+	point, err := NewG2(
+		[2]*big.Int{coordinates[1], coordinates[0]},
+		[2]*big.Int{coordinates[3], coordinates[2]})
+	if err != nil {
+		return nil, &ParseError{Line: line, Column: column, Err: err}
+	}
+	return point, nil
+}
+
+// readGT reads 12 coordinates of Fp12 element
+func (t *tokenizer) readGT() (*GT, error) {
+	// libsnark prints c0 c1 on every level of the tower, while bn256
+	// expects the reverse, so fill the buffer from the end
+	marshalled := make([]byte, 12*32)
+	line, column := 0, 0
+	for i := 11; i >= 0; i-- {
+		c, err := t.readBigInt()
+		if err != nil {
+			return nil, err
+		}
+		if i == 11 {
+			line, column = t.tokenLine, t.tokenColumn
+		}
+		padded, err := padBigInt(c)
+		if err != nil {
+			return nil, t.errorf("%v", err)
+		}
+		copy(marshalled[i*32:], padded)
+	}
+	point := new(GT)
+	_, err := point.Unmarshal(marshalled)
+	if err != nil {
+		return nil, &ParseError{Line: line, Column: column, Err: err}
+	}
+	return point, nil
+}
+
+// ReadInt reads a single unsigned integer
+func ReadInt(r *bufio.Reader) (uint64, error) {
+	return newTokenizer(r).readUint()
 }
 
+// ReadG1 reads a single G1 point
+func ReadG1(r *bufio.Reader) (*G1, error) {
+	return newTokenizer(r).readG1()
+}
+
+// ReadG2 reads a single G2 point
+func ReadG2(r *bufio.Reader) (*G2, error) {
+	return newTokenizer(r).readG2()
+}
+
+// ReadGT reads 12 coordinates of Fp12 element as printed by libsnark
+func ReadGT(r *bufio.Reader) (*GT, error) {
+	return newTokenizer(r).readGT()
+}
+
+// maxDomainSize limits the number of public inputs, so that a malformed
+// key can not make the parser allocate unbounded memory
+const maxDomainSize = 1 << 20
+
+// SparseVector is libsnark accumulation vector, the first element
+// and sparse vector of values at given indices
 type SparseVector struct {
-	first *G1
-	rest  []*G1
+	first      *G1
+	domainSize uint64
+	indices    []uint64
+	rest       []*G1
+}
+
+// dense expands the vector into the first element followed by domainSize
+// points, so that value at index i is at position i+1.
+// Positions without a value are filled with the point at infinity
+func (sv *SparseVector) dense() []*G1 {
+	ic := make([]*G1, sv.domainSize+1)
+	ic[0] = sv.first
+	for i, index := range sv.indices {
+		ic[index+1] = sv.rest[i]
+	}
+	for i := range ic {
+		if ic[i] == nil {
+			ic[i] = new(G1).ScalarBaseMult(big.NewInt(0))
+		}
+	}
+	return ic
 }
 
+// LibsnarkVerifyingKey is r1cs_ppzksnark verifying key as printed by libsnark
 type LibsnarkVerifyingKey struct {
 	A          *G2
 	B          *G1
@@ -178,113 +285,110 @@ type LibsnarkVerifyingKey struct {
 	IC         *SparseVector
 }
 
-// func ParseFromBytes(b []byte) {
-// 	r := bytes.NewReader(b)
-// 	reader := bufio.NewReader(r)
-// 	for {
-// 		_, err := ReadG1(reader)
-// 		if err != nil {
-// 			return
-// 		}
-// 	}
-// }
-
-func (sv *SparseVector) ParseFromReader(r *bufio.Reader) error {
-	first, err := ReadG1(r)
+// ParseFromReader parses accumulation vector
+func (sv *SparseVector) ParseFromReader(r io.Reader) error {
+	return sv.parse(newTokenizer(r))
+}
+
+func (sv *SparseVector) parse(t *tokenizer) error {
+	first, err := t.readG1()
 	if err != nil {
 		return err
 	}
-	ReadInt(r)
-	consumeNewLine(r)
-	indSize, err := ReadInt(r)
+	domainSize, err := t.readUint()
 	if err != nil {
 		return err
 	}
-	consumeNewLine(r)
-	for i := 0; i < int(indSize); i++ {
-		_, err := ReadInt(r)
+	if domainSize > maxDomainSize {
+		return t.errorf("domain of size %d is too large", domainSize)
+	}
+	indicesSize, err := t.readUint()
+	if err != nil {
+		return err
+	}
+	if indicesSize > domainSize {
+		return t.errorf("%d indices for domain of size %d", indicesSize, domainSize)
+	}
+	indices := make([]uint64, indicesSize)
+	for i := range indices {
+		index, err := t.readUint()
 		if err != nil {
 			return err
 		}
-		consumeNewLine(r)
+		if index >= domainSize {
+			return t.errorf("index %d is out of domain of size %d", index, domainSize)
+		}
+		if i != 0 && index <= indices[i-1] {
+			return t.errorf("index %d is not increasing", index)
+		}
+		indices[i] = index
 	}
-	valuesSize, err := ReadInt(r)
+	valuesSize, err := t.readUint()
 	if err != nil {
 		return err
 	}
-	consumeNewLine(r)
-	points := make([]*G1, valuesSize)
-	fmt.Println("Verification has inputs = " + strconv.FormatUint(valuesSize, 10))
-	for i := 0; i < int(valuesSize); i++ {
-		point, err := ReadG1(r)
+	if valuesSize != indicesSize {
+		return t.errorf("%d values for %d indices", valuesSize, indicesSize)
+	}
+	values := make([]*G1, valuesSize)
+	for i := range values {
+		values[i], err = t.readG1()
 		if err != nil {
 			return err
 		}
-		consumeNewLine(r)
-		points[i] = point
 	}
 	sv.first = first
-	sv.rest = points
+	sv.domainSize = domainSize
+	sv.indices = indices
+	sv.rest = values
 	return nil
 }
 
+// ParseFromFile parses r1cs_ppzksnark verifying key
 func (vk *LibsnarkVerifyingKey) ParseFromFile(filename string) error {
 	r, err := os.Open(filename)
-	defer r.Close()
 	if err != nil {
 		return err
 	}
-	reader := bufio.NewReader(r)
-	return vk.ParseFromReader(reader)
+	defer r.Close()
+	return vk.ParseFromReader(r)
 }
 
-func (vk *LibsnarkVerifyingKey) ParseFromReader(reader *bufio.Reader) error {
-	A, err := ReadG2(reader)
+// ParseFromReader parses r1cs_ppzksnark verifying key
+func (vk *LibsnarkVerifyingKey) ParseFromReader(r io.Reader) error {
+	t := newTokenizer(r)
+	A, err := t.readG2()
 	if err != nil {
 		return err
 	}
-	consumeNewLine(reader)
-
-	B, err := ReadG1(reader)
+	B, err := t.readG1()
 	if err != nil {
 		return err
 	}
-	consumeNewLine(reader)
-
-	C, err := ReadG2(reader)
+	C, err := t.readG2()
 	if err != nil {
 		return err
 	}
-	consumeNewLine(reader)
-
-	Gamma, err := ReadG2(reader)
+	Gamma, err := t.readG2()
 	if err != nil {
 		return err
 	}
-	consumeNewLine(reader)
-
-	GammaBeta1, err := ReadG1(reader)
+	GammaBeta1, err := t.readG1()
 	if err != nil {
 		return err
 	}
-	consumeNewLine(reader)
-
-	GammaBeta2, err := ReadG2(reader)
+	GammaBeta2, err := t.readG2()
 	if err != nil {
 		return err
 	}
-	consumeNewLine(reader)
-
-	Z, err := ReadG2(reader)
+	Z, err := t.readG2()
 	if err != nil {
 		return err
 	}
-	consumeNewLine(reader)
-
 	ic := new(SparseVector)
-	err = ic.ParseFromReader(reader)
+	err = ic.parse(t)
 	if err != nil {
-		return nil
+		return err
 	}
 
 	vk.A = A
@@ -297,3 +401,25 @@ func (vk *LibsnarkVerifyingKey) ParseFromReader(reader *bufio.Reader) error {
 	vk.IC = ic
 	return nil
 }
+
+// ToVerifyingKey converts a parsed libsnark key into the form used by the verifiers.
+// Sparse IC is expanded, so the key expects exactly IC.domainSize public inputs
+func (vk *LibsnarkVerifyingKey) ToVerifyingKey() (*VerifyingKey, error) {
+	if vk.IC == nil || vk.IC.first == nil || len(vk.IC.indices) != len(vk.IC.rest) {
+		return nil, errors.New("Verifying key has no IC")
+	}
+	result := &VerifyingKey{
+		A:          vk.A,
+		B:          vk.B,
+		C:          vk.C,
+		Gamma:      vk.Gamma,
+		GammaBeta1: vk.GammaBeta1,
+		GammaBeta2: vk.GammaBeta2,
+		Z:          vk.Z,
+		IC:         vk.IC.dense(),
+	}
+	if err := result.validate(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,138 @@
+package verifier
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/shamatar/go-snarks/curve"
+)
+
+func TestLibsnarkVKParsingErrorPosition(t *testing.T) {
+	content, err := ioutil.ReadFile("../vk_key.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(content), "\n")
+
+	vk := new(LibsnarkVerifyingKey)
+	if err := vk.ParseFromReader(strings.NewReader(string(content))); err != nil {
+		t.Fatal(err)
+	}
+
+	// break the infinity flag of B on the second line
+	broken := append([]string{}, lines...)
+	broken[1] = "2" + broken[1][1:]
+	err = vk.ParseFromReader(strings.NewReader(strings.Join(broken, "\n")))
+	parseErr, ok := err.(*ParseError)
+	if !ok || parseErr.Line != 2 || parseErr.Column != 1 {
+		t.Fatalf("expected error at line 2, column 1, got %v", err)
+	}
+
+	// point that is not on the curve is reported at its flag
+	broken = append([]string{}, lines...)
+	broken[4] = "0 1 3"
+	err = vk.ParseFromReader(strings.NewReader(strings.Join(broken, "\n")))
+	parseErr, ok = err.(*ParseError)
+	if !ok || parseErr.Line != 5 || parseErr.Column != 1 {
+		t.Fatalf("expected error at line 5, column 1, got %v", err)
+	}
+
+	// truncated key
+	err = vk.ParseFromReader(strings.NewReader(strings.Join(lines[:8], "\n")))
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected %v, got %v", io.ErrUnexpectedEOF, err)
+	}
+}
+
+func TestSparseVectorParsing(t *testing.T) {
+	sv := new(SparseVector)
+	err := sv.ParseFromReader(strings.NewReader("0 1 2\n4\n2\n1\n3\n2\n0 1 2\n1 0 1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sv.domainSize != 4 || len(sv.indices) != 2 || sv.indices[0] != 1 || sv.indices[1] != 3 {
+		t.Fatalf("indices are not parsed: %v of %d", sv.indices, sv.domainSize)
+	}
+	if len(sv.rest) != 2 || sv.rest[1].String() != new(G1).ScalarBaseMult(Order).String() {
+		t.Fatal("values are not parsed")
+	}
+
+	invalid := []string{
+		"0 1 2\n2\n1\n2\n1\n0 1 2\n",       // index out of domain
+		"0 1 2\n4\n2\n3\n1\n2\n0 1 2\n",    // not increasing
+		"0 1 2\n4\n1\n1\n2\n0 1 2\n",       // values do not match indices
+		"0 1 2\n4\n1\n1\n1\n3 1 2\n",       // invalid flag
+		"0 1 2\n4\n1\n1\n1\n0 1 2 extra\n", // ok, extra data belongs to the next structure
+	}
+	for i, content := range invalid[:4] {
+		if err := sv.ParseFromReader(strings.NewReader(content)); err == nil {
+			t.Fatalf("invalid vector %d is parsed", i)
+		}
+	}
+	if err := sv.ParseFromReader(strings.NewReader(invalid[4])); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadPointsFromBufferedReader(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("0 1 2\n1 0 1\n42\n"))
+	if _, err := ReadG1(r); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadG1(r); err != nil {
+		t.Fatal(err)
+	}
+	i, err := ReadInt(r)
+	if err != nil || i != 42 {
+		t.Fatalf("expected 42, got %d, %v", i, err)
+	}
+}
+
+func TestToVerifyingKeyExpandsIndices(t *testing.T) {
+	point := libsnarkG1(GetG1Base())
+	content := []string{
+		libsnarkG2(GetG2Base()), point, libsnarkG2(GetG2Base()), libsnarkG2(GetG2Base()),
+		point, libsnarkG2(GetG2Base()), libsnarkG2(GetG2Base()),
+		// IC: domain of 4 inputs, values only for 1 and 3
+		point, "4", "2", "1", "3", "2",
+		libsnarkG1(new(G1).ScalarBaseMult(big.NewInt(2))),
+		libsnarkG1(new(G1).ScalarBaseMult(big.NewInt(3))),
+	}
+	libsnarkVK := new(LibsnarkVerifyingKey)
+	err := libsnarkVK.ParseFromReader(strings.NewReader(strings.Join(content, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vk, err := libsnarkVK.ToVerifyingKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vk.IC) != 5 {
+		t.Fatalf("expected 5 IC points, got %d", len(vk.IC))
+	}
+	zero := new(G1).ScalarBaseMult(big.NewInt(0)).String()
+	expected := []string{GetG1Base().String(), zero, new(G1).ScalarBaseMult(big.NewInt(2)).String(),
+		zero, new(G1).ScalarBaseMult(big.NewInt(3)).String()}
+	for i, p := range vk.IC {
+		if p.String() != expected[i] {
+			t.Fatalf("IC[%d] is %v, expected %v", i, p, expected[i])
+		}
+	}
+
+	// IC[1] and IC[3] are zero, so those inputs do not change the accumulator
+	acc, err := accumulateWitness(curve.BN254, curveG1s(vk.IC), Witness{big.NewInt(5), big.NewInt(1), big.NewInt(7), big.NewInt(1)}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc.(*G1).String() != new(G1).ScalarBaseMult(big.NewInt(6)).String() {
+		t.Fatal("witness is not accumulated properly")
+	}
+	if _, err := accumulateWitness(curve.BN254, curveG1s(vk.IC), Witness{big.NewInt(5), nil, big.NewInt(7), big.NewInt(1)}, 1); err == nil {
+		t.Fatal("nil input is accumulated")
+	}
+}
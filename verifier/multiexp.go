@@ -0,0 +1,174 @@
+package verifier
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"math/bits"
+	"sync"
+
+	"github.com/shamatar/go-snarks/curve"
+)
+
+// naiveMultiExpThreshold is the number of points below which
+// a sum of plain scalar multiplications is faster than the bucket method
+const naiveMultiExpThreshold = 4
+
+// MultiExpG1 computes sum(scalars[i] * points[i]) with the Pippenger bucket method.
+// Scalars are taken mod Order
+func MultiExpG1(points []*G1, scalars []*big.Int) (*G1, error) {
+	return multiExpG1(points, scalars, 1)
+}
+
+// multiExpG1 is multiExp on cloudflare bn256
+func multiExpG1(points []*G1, scalars []*big.Int, workers int) (*G1, error) {
+	sum, err := multiExp(curve.BN254, curveG1s(points), scalars, workers)
+	if err != nil {
+		return nil, err
+	}
+	return sum.(*G1), nil
+}
+
+// multiExp computes sum(scalars[i] * points[i]) on the curve of the points,
+// spreading windows of the bucket method over the workers
+func multiExp(c curve.Curve, points []curve.G1, scalars []*big.Int, workers int) (curve.G1, error) {
+	if len(points) != len(scalars) {
+		return nil, errors.New("Number of points and scalars differ")
+	}
+	order := c.Order()
+	reduced := make([]*big.Int, len(scalars))
+	for i, s := range scalars {
+		if s == nil {
+			return nil, errors.New("Scalar is nil")
+		}
+		if s.Sign() < 0 || s.Cmp(order) >= 0 {
+			s = new(big.Int).Mod(s, order)
+		}
+		reduced[i] = s
+	}
+	if len(points) < naiveMultiExpThreshold {
+		return naiveMultiExp(c, points, reduced), nil
+	}
+	limbs := make([][4]uint64, len(reduced))
+	for i, s := range reduced {
+		limbs[i] = scalarLimbs(s)
+	}
+	return pippenger(c, points, limbs, uint(order.BitLen()), workers), nil
+}
+
+// zeroG1 returns the point at infinity of the curve
+func zeroG1(c curve.Curve) curve.G1 {
+	return c.ScalarMultG1(c.G1Generator(), new(big.Int))
+}
+
+func naiveMultiExp(c curve.Curve, points []curve.G1, scalars []*big.Int) curve.G1 {
+	result := zeroG1(c)
+	for i, p := range points {
+		result = c.AddG1(result, c.ScalarMultG1(p, scalars[i]))
+	}
+	return result
+}
+
+// scalarLimbs splits a reduced scalar into little-endian 64 bit limbs
+func scalarLimbs(s *big.Int) [4]uint64 {
+	var buf [32]byte
+	s.FillBytes(buf[:])
+	var limbs [4]uint64
+	for i := range limbs {
+		limbs[i] = binary.BigEndian.Uint64(buf[32-8*(i+1):])
+	}
+	return limbs
+}
+
+// digit extracts width bits of the scalar starting from the offset
+func digit(limbs *[4]uint64, offset, width uint) uint64 {
+	limb, shift := offset/64, offset%64
+	value := limbs[limb] >> shift
+	if shift+width > 64 && limb+1 < 4 {
+		value |= limbs[limb+1] << (64 - shift)
+	}
+	return value & (1<<width - 1)
+}
+
+// windowSize is roughly log2 of the number of points,
+// which balances bucket additions against the number of windows
+func windowSize(n int) uint {
+	c := uint(bits.Len(uint(n))) - 1
+	if c < 2 {
+		return 2
+	}
+	if c > 16 {
+		return 16
+	}
+	return c
+}
+
+// pippenger splits scalars into windows of width bits. Sums of the windows are
+// independent, so they are computed by the workers and then combined as
+// sum(2^(w*width) * windowSum[w]) with width doublings between the windows
+func pippenger(c curve.Curve, points []curve.G1, scalars [][4]uint64, scalarBits uint, workers int) curve.G1 {
+	width := windowSize(len(points))
+	windows := int((scalarBits + width - 1) / width)
+	sums := make([]curve.G1, windows)
+	if workers <= 1 {
+		for w := range sums {
+			sums[w] = windowSum(c, points, scalars, uint(w), width)
+		}
+	} else {
+		var wg sync.WaitGroup
+		for worker := 0; worker < workers && worker < windows; worker++ {
+			wg.Add(1)
+			go func(worker int) {
+				defer wg.Done()
+				for w := worker; w < windows; w += workers {
+					sums[w] = windowSum(c, points, scalars, uint(w), width)
+				}
+			}(worker)
+		}
+		wg.Wait()
+	}
+
+	result := zeroG1(c)
+	for w := windows - 1; w >= 0; w-- {
+		for i := uint(0); i < width; i++ {
+			result = c.AddG1(result, result)
+		}
+		if sums[w] != nil {
+			result = c.AddG1(result, sums[w])
+		}
+	}
+	return result
+}
+
+// windowSum returns sum(digit[i] * points[i]) for the window or nil if all digits are zero
+func windowSum(c curve.Curve, points []curve.G1, scalars [][4]uint64, w, width uint) curve.G1 {
+	buckets := make([]curve.G1, 1<<width)
+	for i := range points {
+		d := digit(&scalars[i], w*width, width)
+		if d == 0 {
+			continue
+		}
+		if buckets[d] == nil {
+			buckets[d] = points[i]
+		} else {
+			buckets[d] = c.AddG1(buckets[d], points[i])
+		}
+	}
+
+	// sum(d * bucket[d]) is computed as a sum of running sums from the top bucket
+	var sum, runningSum curve.G1
+	for d := len(buckets) - 1; d > 0; d-- {
+		if buckets[d] != nil {
+			if runningSum == nil {
+				runningSum = buckets[d]
+				sum = buckets[d]
+				continue
+			}
+			runningSum = c.AddG1(runningSum, buckets[d])
+		}
+		if runningSum != nil {
+			sum = c.AddG1(sum, runningSum)
+		}
+	}
+	return sum
+}
@@ -0,0 +1,74 @@
+package verifier
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/shamatar/go-snarks/curve"
+)
+
+func randomMultiExp(t testing.TB, n int) ([]*G1, []*big.Int) {
+	points := make([]*G1, n)
+	scalars := make([]*big.Int, n)
+	for i := range points {
+		points[i] = new(G1).ScalarBaseMult(randomScalar(t))
+		scalars[i] = randomScalar(t)
+	}
+	return points, scalars
+}
+
+func TestMultiExpG1(t *testing.T) {
+	for _, n := range []int{0, 1, 3, 4, 17, 100} {
+		points, scalars := randomMultiExp(t, n)
+		// edge cases of the bucket method
+		if n > 3 {
+			scalars[0] = big.NewInt(0)
+			scalars[1] = new(big.Int).Sub(Order, big.NewInt(1))
+			scalars[2] = new(big.Int).Add(Order, big.NewInt(5))
+			points[3] = new(G1).Set(points[2])
+		}
+		result, err := MultiExpG1(points, scalars)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := naiveMultiExp(curve.BN254, curveG1s(points), scalars)
+		if result.String() != expected.(*G1).String() {
+			t.Fatalf("wrong result for %d points", n)
+		}
+	}
+
+	if _, err := MultiExpG1([]*G1{GetG1Base()}, nil); err == nil {
+		t.Fatal("different lengths are accepted")
+	}
+}
+
+func BenchmarkMultiExpG1(b *testing.B) {
+	for _, n := range []int{8, 64, 256, 1024} {
+		points, scalars := randomMultiExp(b, n)
+		b.Run(fmt.Sprintf("naive/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				naiveMultiExp(curve.BN254, curveG1s(points), scalars)
+			}
+		})
+		b.Run(fmt.Sprintf("pippenger/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				MultiExpG1(points, scalars)
+			}
+		})
+	}
+}
+
+func BenchmarkGroth16ManyInputs(b *testing.B) {
+	witness := make(Witness, 256)
+	for i := range witness {
+		witness[i] = randomScalar(b)
+	}
+	vk, proof := groth16Example(b, witness)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := VerifyGroth16(vk, proof, witness); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
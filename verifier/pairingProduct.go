@@ -1,6 +1,6 @@
 package verifier
 
-// uses only fast Cloudflare implementation
+// uses only fast Cloudflare implementation, verifiers for other curves are in curves.go
 
 // TODO use pools for reduced GC
 
@@ -22,6 +22,13 @@ type G2 = bn256.G2
 // GT is a pairing group e(G1, G2)
 type GT = bn256.GT
 
+// Order is the order of G1 and G2 groups, so every scalar
+// (including witness elements) lives in the field of integers mod Order
+var Order = bn256.Order
+
+// P is the prime of the field the curve coordinates live in
+var P = bn256.P
+
 // GTIdentity is e(g1, g2) * 0
 var GTIdentity = new(GT)
 
@@ -47,15 +54,13 @@ func AddG1(data []byte) ([]byte, error) {
 		return nil, errors.New("Data should be 128 bytes long")
 	}
 
-	a := new(G1)
-	_, err := a.Unmarshal(data[:64])
+	a, err := UnmarshalG1(data[:64])
 
 	if err != nil {
 		return nil, err
 	}
 
-	b := new(G1)
-	_, err = b.Unmarshal(data[64:])
+	b, err := UnmarshalG1(data[64:])
 
 	if err != nil {
 		return nil, err
@@ -79,8 +84,7 @@ func MulG1(data []byte) ([]byte, error) {
 	if len(data) != 96 {
 		return nil, errors.New("Data should be 96 bytes long")
 	}
-	point := new(G1)
-	_, err := point.Unmarshal(data[:64])
+	point, err := UnmarshalG1(data[:64])
 	if err != nil {
 		return nil, err
 	}
@@ -95,3 +99,30 @@ func MulG1Parsed(scalar *big.Int, point *G1) *G1 {
 	resultPoint.ScalarMult(point, scalar)
 	return resultPoint
 }
+
+// PairingCheckBytes parses raw data and does a pairing check as ecPairing precompile.
+// Expects k * (64 + 128) bytes of data, returns 32 bytes word 1 on success and 0 otherwise
+func PairingCheckBytes(data []byte) ([]byte, error) {
+	if len(data)%192 != 0 {
+		return nil, errors.New("Data length should be a multiple of 192 bytes")
+	}
+	a := make([]*G1, 0, len(data)/192)
+	b := make([]*G2, 0, len(data)/192)
+	for i := 0; i < len(data); i += 192 {
+		g1, err := UnmarshalG1(data[i : i+64])
+		if err != nil {
+			return nil, err
+		}
+		g2, err := UnmarshalG2(data[i+64 : i+192])
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, g1)
+		b = append(b, g2)
+	}
+	result := make([]byte, 32)
+	if PairingCheck(a, b) {
+		result[31] = 1
+	}
+	return result, nil
+}
@@ -0,0 +1,174 @@
+package verifier
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	"github.com/shamatar/go-snarks/curve"
+	preparedbn256 "github.com/shamatar/go-snarks/internal/bn256"
+)
+
+// millerProduct computes the product of Miller loops e(a[i], b[i]) without
+// the final exponentiation. Loops are spread over at most workers goroutines,
+// every one of them stops as soon as the context is done
+func millerProduct(ctx context.Context, a []*G1, b []*G2, workers int) (*GT, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(a) {
+		workers = len(a)
+	}
+	products := make([]*GT, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := worker; i < len(a); i += workers {
+				if err := ctx.Err(); err != nil {
+					errs[worker] = err
+					return
+				}
+				loop := bn256.Miller(a[i], b[i])
+				if products[worker] == nil {
+					products[worker] = loop
+				} else {
+					products[worker].Add(products[worker], loop)
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	product := new(GT).Set(GTIdentity)
+	for worker := range products {
+		if errs[worker] != nil {
+			return nil, errs[worker]
+		}
+		if products[worker] != nil {
+			product.Add(product, products[worker])
+		}
+	}
+	return product, nil
+}
+
+// pairingCheckContext is PairingCheck with Miller loops over a pool of workers
+// and a single final exponentiation
+func pairingCheckContext(ctx context.Context, a []*G1, b []*G2, workers int) (bool, error) {
+	product, err := millerProduct(ctx, a, b, workers)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(product.Finalize().Marshal(), IdentityBytes), nil
+}
+
+// curvePairingCheck is pairingCheckContext on the curve of the points.
+// Only cloudflare bn256 exposes Miller loops, so the product is checked
+// at once on other curves
+func curvePairingCheck(ctx context.Context, c curve.Curve, a []curve.G1, b []curve.G2, workers int) (bool, error) {
+	if c != curve.BN254 {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		return c.PairingCheck(a, b), nil
+	}
+	g1, g2 := bn254Points(a, b)
+	return pairingCheckContext(ctx, g1, g2, workers)
+}
+
+// bn254Points returns the points of curve.BN254 as cloudflare bn256 points
+func bn254Points(a []curve.G1, b []curve.G2) ([]*G1, []*G2) {
+	g1 := make([]*G1, len(a))
+	g2 := make([]*G2, len(b))
+	for i := range a {
+		g1[i] = a[i].(*G1)
+		g2[i] = b[i].(*G2)
+	}
+	return g1, g2
+}
+
+// preparedG1 copies the point to the bn256 fork with prepared Miller loops
+func preparedG1(p *G1) (*preparedbn256.G1, error) {
+	moved := new(preparedbn256.G1)
+	if _, err := moved.Unmarshal(new(G1).Set(p).Marshal()); err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
+
+// prepareG2 computes the lines of the Miller loop of the point
+func prepareG2(p *G2) (*preparedbn256.G2Prepared, error) {
+	moved := new(preparedbn256.G2)
+	if _, err := moved.Unmarshal(new(G2).Set(p).Marshal()); err != nil {
+		return nil, err
+	}
+	return moved.Prepare(), nil
+}
+
+// preparedPairingCheck is pairingCheckContext that takes the lines of G2 points
+// from lines when they are there. Every worker runs a single Miller loop over
+// its share of the pairs
+func preparedPairingCheck(ctx context.Context, a []*G1, b []*G2, lines map[*G2]*preparedbn256.G2Prepared, workers int) (bool, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(a) {
+		workers = len(a)
+	}
+	products := make([]*preparedbn256.GT, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			var g1 []*preparedbn256.G1
+			var g2 []*preparedbn256.G2Prepared
+			for i := worker; i < len(a); i += workers {
+				if err := ctx.Err(); err != nil {
+					errs[worker] = err
+					return
+				}
+				p, err := preparedG1(a[i])
+				if err != nil {
+					errs[worker] = err
+					return
+				}
+				q, ok := lines[b[i]]
+				if !ok {
+					if q, err = prepareG2(b[i]); err != nil {
+						errs[worker] = err
+						return
+					}
+				}
+				g1 = append(g1, p)
+				g2 = append(g2, q)
+			}
+			if err := ctx.Err(); err != nil {
+				errs[worker] = err
+				return
+			}
+			products[worker] = preparedbn256.MillerPrepared(g1, g2)
+		}(worker)
+	}
+	wg.Wait()
+
+	var product *preparedbn256.GT
+	for worker := range products {
+		if errs[worker] != nil {
+			return false, errs[worker]
+		}
+		if product == nil {
+			product = products[worker]
+		} else {
+			product.Add(product, products[worker])
+		}
+	}
+	if product == nil {
+		return true, nil
+	}
+	return product.Finalize().IsOne(), nil
+}
@@ -0,0 +1,71 @@
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestParallelVerification(t *testing.T) {
+	vk, proof, witness := zokratesExample(t)
+	for _, workers := range []int{0, 1, 3, 16} {
+		err := Verify(vk, proof, witness, WithStrategy(AggregateStrategy), WithWorkers(workers))
+		if err != nil {
+			t.Fatalf("%d workers: %v", workers, err)
+		}
+	}
+	if err := Verify(vk, proof, witness, WithWorkers(3)); err != nil {
+		t.Fatal(err)
+	}
+	corrupted := *proof
+	corrupted.K = proof.A
+	for _, strategy := range []Strategy{SplitStrategy, AggregateStrategy} {
+		err := Verify(vk, &corrupted, witness, WithStrategy(strategy), WithWorkers(4))
+		if err != ErrSameCoefficients {
+			t.Fatalf("expected %v, got %v", ErrSameCoefficients, err)
+		}
+	}
+
+	_, proofs, inputs := exampleBatch(t, 8)
+	if err := BatchVerify(vk, proofs, inputs, WithWorkers(4)); err != nil {
+		t.Fatal(err)
+	}
+
+	// multi-exponentiation gives the same result on any number of workers
+	points, scalars := randomMultiExp(t, 50)
+	expected, _ := MultiExpG1(points, scalars)
+	parallel, err := multiExpG1(points, scalars, 5)
+	if err != nil || parallel.String() != expected.String() {
+		t.Fatal("parallel multi-exponentiation differs")
+	}
+}
+
+func TestVerifyContextCancelled(t *testing.T) {
+	vk, proof, witness := zokratesExample(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, strategy := range []Strategy{SplitStrategy, AggregateStrategy} {
+		err := VerifyContext(ctx, vk, proof, witness, WithStrategy(strategy), WithWorkers(2))
+		if err != context.Canceled {
+			t.Fatalf("expected %v, got %v", context.Canceled, err)
+		}
+	}
+
+	pvk, _ := NewPreparedVerifyingKey(vk)
+	_, proofs, inputs := exampleBatch(t, 4)
+	if err := pvk.BatchVerifyContext(ctx, proofs, inputs); err != context.Canceled {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func BenchmarkParallelVerification(b *testing.B) {
+	vk, proof, witness := zokratesExample(b)
+	pvk, _ := NewPreparedVerifyingKey(vk)
+	for _, workers := range []int{1, 4} {
+		b.Run(fmt.Sprintf("workers/%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				pvk.Verify(proof, witness, WithStrategy(AggregateStrategy), WithWorkers(workers))
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/shamatar/go-snarks/curve"
+	preparedbn256 "github.com/shamatar/go-snarks/internal/bn256"
+)
+
+// PreparedVerifyingKey is a validated VerifyingKey with the negated
+// GammaBeta1 computed once, for servers checking many proofs.
+// The aggregated check merges all pairings on the same G2 point of the key,
+// which takes 7 Miller loops per proof instead of 13.
+// On curve.BN254 lines of the Miller loops of vk.A, vk.C, vk.Gamma,
+// vk.GammaBeta2, vk.Z and the G2 generator are computed once as well, and
+// the pairings of a check share a single loop. Other curves do not expose
+// Miller loops, so their keys only have the constants.
+// Equations are written against curve.Curve, keys of cloudflare bn256 are
+// checked on curve.BN254 without copying the points
+type PreparedVerifyingKey struct {
+	vk            *CurveVerifyingKey
+	g2Base        curve.G2
+	negGammaBeta1 curve.G1
+	lines         map[*G2]*preparedbn256.G2Prepared
+}
+
+// NewPreparedVerifyingKey validates and prepares the key.
+// The points are copied, so the key may be changed afterwards
+func NewPreparedVerifyingKey(vk *VerifyingKey) (*PreparedVerifyingKey, error) {
+	if err := vk.validate(); err != nil {
+		return nil, err
+	}
+	copied := &VerifyingKey{
+		A:          new(G2).Set(vk.A),
+		B:          new(G1).Set(vk.B),
+		C:          new(G2).Set(vk.C),
+		Gamma:      new(G2).Set(vk.Gamma),
+		GammaBeta1: new(G1).Set(vk.GammaBeta1),
+		GammaBeta2: new(G2).Set(vk.GammaBeta2),
+		Z:          new(G2).Set(vk.Z),
+		IC:         make([]*G1, len(vk.IC)),
+	}
+	for i, p := range vk.IC {
+		copied.IC[i] = new(G1).Set(p)
+	}
+	return prepare(copied), nil
+}
+
+// prepare does not validate or copy the key
+func prepare(vk *VerifyingKey) *PreparedVerifyingKey {
+	return prepareCurve(vk.onBN254())
+}
+
+// prepareCurve does not validate or copy the key
+func prepareCurve(vk *CurveVerifyingKey) *PreparedVerifyingKey {
+	pvk := &PreparedVerifyingKey{
+		vk:            vk,
+		g2Base:        vk.Curve.G2Generator(),
+		negGammaBeta1: vk.Curve.NegG1(vk.GammaBeta1),
+	}
+	if vk.Curve == curve.BN254 {
+		pvk.lines = make(map[*G2]*preparedbn256.G2Prepared)
+		for _, p := range []curve.G2{pvk.g2Base, vk.A, vk.C, vk.Gamma, vk.GammaBeta2, vk.Z} {
+			lines, err := prepareG2(p.(*G2))
+			if err != nil {
+				// the check computes the lines again and reports the point
+				continue
+			}
+			pvk.lines[p.(*G2)] = lines
+		}
+	}
+	return pvk
+}
+
+// pairingCheck is curvePairingCheck that takes the lines of the key points from
+// the prepared key
+func (pvk *PreparedVerifyingKey) pairingCheck(ctx context.Context, a []curve.G1, b []curve.G2, workers int) (bool, error) {
+	if pvk.lines == nil {
+		return curvePairingCheck(ctx, pvk.vk.Curve, a, b, workers)
+	}
+	g1, g2 := bn254Points(a, b)
+	return preparedPairingCheck(ctx, g1, g2, pvk.lines, workers)
+}
+
+// Verify checks a proof the same way as Verify does
+func (pvk *PreparedVerifyingKey) Verify(proof *Proof, inputs Witness, opts ...Option) error {
+	return pvk.VerifyContext(context.Background(), proof, inputs, opts...)
+}
+
+// VerifyContext checks a proof the same way as VerifyContext does
+func (pvk *PreparedVerifyingKey) VerifyContext(ctx context.Context, proof *Proof, inputs Witness, opts ...Option) error {
+	o := newOptions(opts)
+	if err := proof.validate(); err != nil {
+		return err
+	}
+	if o.rejectIdentity {
+		if err := proof.checkIdentity(); err != nil {
+			return err
+		}
+	}
+	return pvk.verify(ctx, proof.onBN254(), inputs, o)
+}
+
+// verify checks a validated proof with the strategy of the options
+func (pvk *PreparedVerifyingKey) verify(ctx context.Context, proof *CurveProof, inputs Witness, o *options) error {
+	inputs, err := checkWitnessOf(inputs, len(pvk.vk.IC), pvk.vk.Curve.Order(), o.reduceWitness)
+	if err != nil {
+		return err
+	}
+
+	switch o.strategy {
+	case SplitStrategy:
+		return pvk.splitVerification(ctx, inputs, proof, o.workers)
+	case AggregateStrategy:
+		success, err := pvk.batchCheck(ctx, []*CurveProof{proof}, []Witness{inputs}, []int{0}, o)
+		if err != nil || success {
+			return err
+		}
+		if splitErr := pvk.splitVerification(ctx, inputs, proof, o.workers); splitErr != nil {
+			return splitErr
+		}
+		return ErrAggregateCheck
+	}
+	return errors.New("Unknown verification strategy")
+}
+
+// BatchVerify checks many proofs the same way as BatchVerify does
+func (pvk *PreparedVerifyingKey) BatchVerify(proofs []Proof, inputs []Witness, opts ...Option) error {
+	return pvk.BatchVerifyContext(context.Background(), proofs, inputs, opts...)
+}
+
+// BatchVerifyContext is BatchVerify that gives up with the context error once the context is done
+func (pvk *PreparedVerifyingKey) BatchVerifyContext(ctx context.Context, proofs []Proof, inputs []Witness, opts ...Option) error {
+	o := newOptions(opts)
+	if len(proofs) != len(inputs) {
+		return fmt.Errorf("Got %d proofs, but %d witnesses", len(proofs), len(inputs))
+	}
+	moved := make([]*CurveProof, len(proofs))
+	for i := range proofs {
+		if proofs[i].validate() == nil && !(o.rejectIdentity && proofs[i].checkIdentity() != nil) {
+			moved[i] = proofs[i].onBN254()
+		}
+	}
+	return pvk.batchVerify(ctx, moved, inputs, o)
+}
+
+// batchVerify checks the proofs that passed validation, the rest are nil
+func (pvk *PreparedVerifyingKey) batchVerify(ctx context.Context, proofs []*CurveProof, inputs []Witness, o *options) error {
+	invalid := make([]int, 0)
+	candidates := make([]int, 0, len(proofs))
+	checked := make([]Witness, len(inputs))
+	for i := range proofs {
+		witness, err := checkWitnessOf(inputs[i], len(pvk.vk.IC), pvk.vk.Curve.Order(), o.reduceWitness)
+		if err != nil || proofs[i] == nil {
+			invalid = append(invalid, i)
+			continue
+		}
+		checked[i] = witness
+		candidates = append(candidates, i)
+	}
+	failed, err := pvk.bisectBatch(ctx, proofs, checked, candidates, o)
+	if err != nil {
+		return err
+	}
+	invalid = mergeSorted(invalid, failed)
+	if len(invalid) != 0 {
+		return &BatchError{Invalid: invalid}
+	}
+	return nil
+}
+
+// splitVerification checks the five equations one by one, Miller loops of
+// every equation and the witness accumulator are spread over workers
+func (pvk *PreparedVerifyingKey) splitVerification(ctx context.Context, witness Witness, proof *CurveProof, workers int) error {
+	vk := pvk.vk
+	c := vk.Curve
+	if len(witness)+1 != len(vk.IC) {
+		return ErrInvalidWitnessLength
+	}
+	witnessAccumulator, err := accumulateWitness(c, vk.IC, witness, workers)
+	if err != nil {
+		return err
+	}
+	t := c.NegG1(c.AddG1(c.AddG1(witnessAccumulator, proof.A), proof.C))
+	u := c.AddG1(witnessAccumulator, proof.A)
+	equations := []struct {
+		a   []curve.G1
+		b   []curve.G2
+		err error
+	}{
+		// e(proof.A, vk.A) == e(proof.Ap, G2)
+		{[]curve.G1{proof.A, c.NegG1(proof.Ap)}, []curve.G2{vk.A, pvk.g2Base}, ErrKnowledgeA},
+		// e(vk.B, proof.B) == e(proof.Bp, G2)
+		{[]curve.G1{vk.B, c.NegG1(proof.Bp)}, []curve.G2{proof.B, pvk.g2Base}, ErrKnowledgeB},
+		// e(proof.C, vk.C) == e(proof.Cp, G2)
+		{[]curve.G1{proof.C, c.NegG1(proof.Cp)}, []curve.G2{vk.C, pvk.g2Base}, ErrKnowledgeC},
+		// e(proof.K, vk.Gamma) + e(- witnessAccumulator - proof.A - proof.C, vk.GammaBeta2) + e(-vk.GammaBeta1, proof.B)
+		{[]curve.G1{proof.K, t, pvk.negGammaBeta1}, []curve.G2{vk.Gamma, vk.GammaBeta2, proof.B}, ErrSameCoefficients},
+		// e(witnessAccumulator + proof.A, proof.B) + e(- proof.H, vk.Z) + e(-proof.C, G2)
+		{[]curve.G1{u, c.NegG1(proof.H), c.NegG1(proof.C)}, []curve.G2{proof.B, vk.Z, pvk.g2Base}, ErrDivisibility},
+	}
+	for _, equation := range equations {
+		success, err := pvk.pairingCheck(ctx, equation.a, equation.b, workers)
+		if err != nil {
+			return err
+		}
+		if !success {
+			return equation.err
+		}
+	}
+	return nil
+}
+
+// bisectBatch returns indices of invalid proofs from the set
+func (pvk *PreparedVerifyingKey) bisectBatch(ctx context.Context, proofs []*CurveProof, inputs []Witness, indices []int, o *options) ([]int, error) {
+	if len(indices) == 0 {
+		return nil, nil
+	}
+	success, err := pvk.batchCheck(ctx, proofs, inputs, indices, o)
+	if err != nil {
+		return nil, err
+	}
+	if success {
+		return nil, nil
+	}
+	if len(indices) == 1 {
+		return indices, nil
+	}
+	middle := len(indices) / 2
+	left, err := pvk.bisectBatch(ctx, proofs, inputs, indices[:middle], o)
+	if err != nil {
+		return nil, err
+	}
+	right, err := pvk.bisectBatch(ctx, proofs, inputs, indices[middle:], o)
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
+
+// batchCheck runs a single pairing check over the proofs with given indices
+func (pvk *PreparedVerifyingKey) batchCheck(ctx context.Context, proofs []*CurveProof, inputs []Witness, indices []int, o *options) (bool, error) {
+	vk := pvk.vk
+	c := vk.Curve
+	// accumulators for the G2 points from the key
+	baseAcc := zeroG1(c)
+	aAcc, cAcc, gammaAcc, gammaBeta2Acc, zAcc := baseAcc, baseAcc, baseAcc, baseAcc, baseAcc
+
+	a := make([]curve.G1, 0, len(indices)+6)
+	b := make([]curve.G2, 0, len(indices)+6)
+
+	for _, i := range indices {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		proof := proofs[i]
+		r := make([]*big.Int, 5)
+		for j := range r {
+			coefficient, err := randomCoefficient(o.random)
+			if err != nil {
+				return false, err
+			}
+			r[j] = coefficient
+		}
+		witnessAccumulator, err := accumulateWitness(c, vk.IC, inputs[i], o.workers)
+		if err != nil {
+			return false, err
+		}
+
+		// e(proof.A, vk.A) + e(-proof.Ap, G2)
+		aAcc = c.AddG1(aAcc, c.ScalarMultG1(proof.A, r[0]))
+		baseAcc = c.AddG1(baseAcc, c.ScalarMultG1(c.NegG1(proof.Ap), r[0]))
+
+		// e(vk.B, proof.B) + e(-proof.Bp, G2)
+		bAcc := c.ScalarMultG1(vk.B, r[1])
+		baseAcc = c.AddG1(baseAcc, c.ScalarMultG1(c.NegG1(proof.Bp), r[1]))
+
+		// e(proof.C, vk.C) + e(-proof.Cp, G2)
+		cAcc = c.AddG1(cAcc, c.ScalarMultG1(proof.C, r[2]))
+		baseAcc = c.AddG1(baseAcc, c.ScalarMultG1(c.NegG1(proof.Cp), r[2]))
+
+		// e(proof.K, vk.Gamma) + e(- witnessAccumulator - proof.A - proof.C, vk.GammaBeta2) + e(-vk.GammaBeta1, proof.B)
+		t := c.NegG1(c.AddG1(c.AddG1(witnessAccumulator, proof.A), proof.C))
+		gammaAcc = c.AddG1(gammaAcc, c.ScalarMultG1(proof.K, r[3]))
+		gammaBeta2Acc = c.AddG1(gammaBeta2Acc, c.ScalarMultG1(t, r[3]))
+		bAcc = c.AddG1(bAcc, c.ScalarMultG1(pvk.negGammaBeta1, r[3]))
+
+		// e(witnessAccumulator + proof.A, proof.B) + e(- proof.H, vk.Z) + e(-proof.C, G2)
+		u := c.AddG1(witnessAccumulator, proof.A)
+		bAcc = c.AddG1(bAcc, c.ScalarMultG1(u, r[4]))
+		zAcc = c.AddG1(zAcc, c.ScalarMultG1(c.NegG1(proof.H), r[4]))
+		baseAcc = c.AddG1(baseAcc, c.ScalarMultG1(c.NegG1(proof.C), r[4]))
+
+		a = append(a, bAcc)
+		b = append(b, proof.B)
+	}
+	a = append(a, baseAcc, aAcc, cAcc, gammaAcc, gammaBeta2Acc, zAcc)
+	b = append(b, pvk.g2Base, vk.A, vk.C, vk.Gamma, vk.GammaBeta2, vk.Z)
+	return pvk.pairingCheck(ctx, a, b, o.workers)
+}
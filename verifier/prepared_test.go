@@ -0,0 +1,52 @@
+package verifier
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestPreparedVerifyingKey(t *testing.T) {
+	vk, proof, witness := zokratesExample(t)
+	pvk, err := NewPreparedVerifyingKey(vk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// lines of the G2 points of the key are computed once
+	if len(pvk.lines) != 6 {
+		t.Fatalf("%d points of the key are prepared", len(pvk.lines))
+	}
+	// the key is copied
+	vk.A = new(G2).ScalarBaseMult(big.NewInt(3))
+
+	for _, strategy := range []Strategy{SplitStrategy, AggregateStrategy} {
+		if err := pvk.Verify(proof, witness, WithStrategy(strategy)); err != nil {
+			t.Fatal(err)
+		}
+		corrupted := *proof
+		corrupted.H = new(G1).Add(proof.H, GetG1Base())
+		if err := pvk.Verify(&corrupted, witness, WithStrategy(strategy)); err != ErrDivisibility {
+			t.Fatalf("expected %v, got %v", ErrDivisibility, err)
+		}
+	}
+	if err := Verify(vk, proof, witness); err != ErrKnowledgeA {
+		t.Fatalf("expected %v, got %v", ErrKnowledgeA, err)
+	}
+
+	if _, err := NewPreparedVerifyingKey(&VerifyingKey{}); err != ErrInvalidVerifyingKey {
+		t.Fatalf("expected %v, got %v", ErrInvalidVerifyingKey, err)
+	}
+}
+
+func BenchmarkPreparedVerification(b *testing.B) {
+	vk, proof, witness := zokratesExample(b)
+	pvk, err := NewPreparedVerifyingKey(vk)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := pvk.Verify(proof, witness, WithStrategy(AggregateStrategy)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
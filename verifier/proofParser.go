@@ -1,8 +1,6 @@
 package verifier
 
 import (
-	"errors"
-	"fmt"
 	"io/ioutil"
 	"strings"
 )
@@ -22,177 +20,52 @@ func ParseProofFromFile(filename string) (*Proof, error) {
 	return ParseProofFromString(fullContent)
 }
 
+// ParseProofFromString parses a proof in libsnark text format as Proof.UnmarshalText does,
+// but also fails if there is something after the proof
 func ParseProofFromString(content string) (*Proof, error) {
-	fullContent := strings.Replace(content, "\n", " ", -1)
-	fullContent = strings.Replace(fullContent, "\t", " ", -1)
-
-	elements := strings.Split(fullContent, " 0 ")
-	firstElem := elements[0]
-	firstElemSplit := strings.Split(firstElem, " ")
-	elements[0] = firstElemSplit[1] + " " + firstElemSplit[2]
-	// if len(elements) != 8 {
-	// 	return nil, errors.New("Invalid number of elements")
-	// }
-	i := 0
-	proof := &Proof{}
-	for _, elem := range elements {
-		cleaned := strings.Trim(elem, " \n\t")
-		components := strings.Split(cleaned, " ")
-		switch i {
-		case 0:
-			A, err := NewG1FromStrings(components[0], components[1], 10)
-			if err != nil {
-				return nil, err
-			}
-			proof.A = A
-		case 1:
-			Ap, err := NewG1FromStrings(components[0], components[1], 10)
-			if err != nil {
-				return nil, err
-			}
-			proof.Ap = Ap
-		case 2:
-			fmt.Println(elem)
-			fmt.Println("Cleaned")
-			fmt.Println(cleaned)
-			fmt.Println("Components")
-			fmt.Println(components)
-			B, err := NewG2FromStrings([2]string{components[0], components[1]}, [2]string{components[2], components[3]}, 10)
-			if err != nil {
-				i++
-				continue
-				return nil, err
-			}
-			proof.B = B
-		case 3:
-			Bp, err := NewG1FromStrings(components[0], components[1], 10)
-			if err != nil {
-				return nil, err
-			}
-			proof.Bp = Bp
-		case 4:
-			C, err := NewG1FromStrings(components[0], components[1], 10)
-			if err != nil {
-				return nil, err
-			}
-			proof.C = C
-		case 5:
-			Cp, err := NewG1FromStrings(components[0], components[1], 10)
-			if err != nil {
-				return nil, err
-			}
-			proof.Cp = Cp
-		case 6:
-			H, err := NewG1FromStrings(components[0], components[1], 10)
-			if err != nil {
-				return nil, err
-			}
-			proof.H = H
-		case 7:
-			K, err := NewG1FromStrings(components[0], components[1], 10)
-			if err != nil {
-				return nil, err
-			}
-			proof.K = K
-		default:
-			return nil, errors.New("Invalid number of elements")
-		}
-		i++
+	t := newTokenizer(strings.NewReader(content))
+	proof, err := t.readProof()
+	if err != nil {
+		return nil, err
 	}
-	if i != 8 {
-		return nil, errors.New("Invalid number of elements")
+	if token, err := t.next(); err == nil {
+		return nil, t.errorf("unexpected %q after the proof", token)
 	}
 	return proof, nil
 }
 
-func ParseProofInLibsnarkFormat(filename string) (*Proof, error) {
-	// file, err := os.Open(filename) // For read access.
-	// if err != nil {
-	// 	return nil, err
-	// }
-	// defer file.Close()
-	fullFile, err := ioutil.ReadFile(filename)
-	fullContent := string(fullFile)
-	fullContent = strings.Replace(fullContent, "\n", " ", -1)
-	fullContent = strings.Replace(fullContent, "\t", " ", -1)
-	if err != nil {
+// readProof reads A, Ap, B, Bp, C, Cp, H and K
+func (t *tokenizer) readProof() (*Proof, error) {
+	proof := &Proof{}
+	var err error
+	if proof.A, err = t.readG1(); err != nil {
 		return nil, err
 	}
-	elements := strings.Split(fullContent, " 0 ")
-	firstElem := elements[0]
-	firstElemSplit := strings.Split(firstElem, " ")
-	elements[0] = firstElemSplit[1] + " " + firstElemSplit[2]
-	// if len(elements) != 8 {
-	// 	return nil, errors.New("Invalid number of elements")
-	// }
-	i := 0
-	proof := &Proof{}
-	for _, elem := range elements {
-		cleaned := strings.Trim(elem, " \n\t")
-		components := strings.Split(cleaned, " ")
-		switch i {
-		case 0:
-			A, err := NewG1FromStrings(components[0], components[1], 10)
-			if err != nil {
-				return nil, err
-			}
-			proof.A = A
-		case 1:
-			Ap, err := NewG1FromStrings(components[0], components[1], 10)
-			if err != nil {
-				return nil, err
-			}
-			proof.Ap = Ap
-		case 2:
-			fmt.Println(elem)
-			fmt.Println("Cleaned")
-			fmt.Println(cleaned)
-			fmt.Println("Components")
-			fmt.Println(components)
-			B, err := NewG2FromStrings([2]string{components[0], components[1]}, [2]string{components[2], components[3]}, 10)
-			if err != nil {
-				i++
-				continue
-				return nil, err
-			}
-			proof.B = B
-		case 3:
-			Bp, err := NewG1FromStrings(components[0], components[1], 10)
-			if err != nil {
-				return nil, err
-			}
-			proof.Bp = Bp
-		case 4:
-			C, err := NewG1FromStrings(components[0], components[1], 10)
-			if err != nil {
-				return nil, err
-			}
-			proof.C = C
-		case 5:
-			Cp, err := NewG1FromStrings(components[0], components[1], 10)
-			if err != nil {
-				return nil, err
-			}
-			proof.Cp = Cp
-		case 6:
-			H, err := NewG1FromStrings(components[0], components[1], 10)
-			if err != nil {
-				return nil, err
-			}
-			proof.H = H
-		case 7:
-			K, err := NewG1FromStrings(components[0], components[1], 10)
-			if err != nil {
-				return nil, err
-			}
-			proof.K = K
-		default:
-			return nil, errors.New("Invalid number of elements")
-		}
-		i++
+	if proof.Ap, err = t.readG1(); err != nil {
+		return nil, err
 	}
-	if i != 8 {
-		return nil, errors.New("Invalid number of elements")
+	if proof.B, err = t.readG2(); err != nil {
+		return nil, err
+	}
+	if proof.Bp, err = t.readG1(); err != nil {
+		return nil, err
+	}
+	if proof.C, err = t.readG1(); err != nil {
+		return nil, err
+	}
+	if proof.Cp, err = t.readG1(); err != nil {
+		return nil, err
+	}
+	if proof.H, err = t.readG1(); err != nil {
+		return nil, err
+	}
+	if proof.K, err = t.readG1(); err != nil {
+		return nil, err
 	}
 	return proof, nil
 }
+
+// ParseProofInLibsnarkFormat is the same as ParseProofFromFile
+func ParseProofInLibsnarkFormat(filename string) (*Proof, error) {
+	return ParseProofFromFile(filename)
+}
@@ -0,0 +1,686 @@
+package verifier
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// Keys and proofs can be written in three formats
+// - text is libsnark text format, the same as ParseFromReader reads
+// - binary is concatenation of points as they are marshalled by bn256,
+//   lengths of IC are big endian integers
+// - JSON has points as 0x prefixed hex coordinates, G2 coordinates are
+//   in the same order as for NewG2FromStrings, so it's ZoKrates compatible
+
+const (
+	g1Size = 64
+	g2Size = 128
+)
+
+var errShortData = errors.New("Data is too short")
+
+func isInfinity(marshalled []byte) bool {
+	for _, b := range marshalled {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// text format
+
+func writeG1Text(w io.Writer, p *G1) {
+	marshalled := p.Marshal()
+	if isInfinity(marshalled) {
+		fmt.Fprint(w, "1 0 1")
+		return
+	}
+	x := new(big.Int).SetBytes(marshalled[:32])
+	y := new(big.Int).SetBytes(marshalled[32:])
+	fmt.Fprintf(w, "0 %s %s", x, y)
+}
+
+func writeG2Text(w io.Writer, p *G2) {
+	marshalled := p.Marshal()
+	if isInfinity(marshalled) {
+		fmt.Fprint(w, "1 0 0 1 0")
+		return
+	}
+	c := make([]*big.Int, 4)
+	for i := range c {
+		c[i] = new(big.Int).SetBytes(marshalled[i*32 : (i+1)*32])
+	}
+	// libsnark prints Fp2 elements as c0 c1, while bn256 marshals c1 c0
+	fmt.Fprintf(w, "0 %s %s %s %s", c[1], c[0], c[3], c[2])
+}
+
+func (sv *SparseVector) writeText(w io.Writer) {
+	writeG1Text(w, sv.first)
+	fmt.Fprintf(w, "\n%d\n%d\n", sv.domainSize, len(sv.indices))
+	for _, index := range sv.indices {
+		fmt.Fprintf(w, "%d\n", index)
+	}
+	fmt.Fprintf(w, "%d\n", len(sv.rest))
+	for _, p := range sv.rest {
+		writeG1Text(w, p)
+		fmt.Fprint(w, "\n")
+	}
+}
+
+// toSparseVector makes accumulation vector with all the indices present
+func toSparseVector(ic []*G1) *SparseVector {
+	sv := &SparseVector{
+		first:      ic[0],
+		domainSize: uint64(len(ic) - 1),
+		indices:    make([]uint64, len(ic)-1),
+		rest:       ic[1:],
+	}
+	for i := range sv.indices {
+		sv.indices[i] = uint64(i)
+	}
+	return sv
+}
+
+// MarshalText writes the key in libsnark text format
+func (vk *LibsnarkVerifyingKey) MarshalText() ([]byte, error) {
+	if vk.IC == nil || vk.IC.first == nil {
+		return nil, ErrInvalidVerifyingKey
+	}
+	var buf bytes.Buffer
+	for _, p := range []interface{}{vk.A, vk.B, vk.C, vk.Gamma, vk.GammaBeta1, vk.GammaBeta2, vk.Z} {
+		switch point := p.(type) {
+		case *G1:
+			if point == nil {
+				return nil, ErrInvalidVerifyingKey
+			}
+			writeG1Text(&buf, point)
+		case *G2:
+			if point == nil {
+				return nil, ErrInvalidVerifyingKey
+			}
+			writeG2Text(&buf, point)
+		}
+		buf.WriteString("\n")
+	}
+	vk.IC.writeText(&buf)
+	return buf.Bytes(), nil
+}
+
+// UnmarshalText parses the key in libsnark text format
+func (vk *LibsnarkVerifyingKey) UnmarshalText(text []byte) error {
+	return vk.ParseFromReader(bytes.NewReader(text))
+}
+
+// toLibsnark is the inverse of LibsnarkVerifyingKey.ToVerifyingKey
+func (vk *VerifyingKey) toLibsnark() (*LibsnarkVerifyingKey, error) {
+	if err := vk.validate(); err != nil {
+		return nil, err
+	}
+	return &LibsnarkVerifyingKey{
+		A:          vk.A,
+		B:          vk.B,
+		C:          vk.C,
+		Gamma:      vk.Gamma,
+		GammaBeta1: vk.GammaBeta1,
+		GammaBeta2: vk.GammaBeta2,
+		Z:          vk.Z,
+		IC:         toSparseVector(vk.IC),
+	}, nil
+}
+
+// MarshalText writes the key in libsnark text format
+func (vk *VerifyingKey) MarshalText() ([]byte, error) {
+	libsnarkVK, err := vk.toLibsnark()
+	if err != nil {
+		return nil, err
+	}
+	return libsnarkVK.MarshalText()
+}
+
+// UnmarshalText parses the key in libsnark text format
+func (vk *VerifyingKey) UnmarshalText(text []byte) error {
+	libsnarkVK := new(LibsnarkVerifyingKey)
+	if err := libsnarkVK.UnmarshalText(text); err != nil {
+		return err
+	}
+	parsed, err := libsnarkVK.ToVerifyingKey()
+	if err != nil {
+		return err
+	}
+	*vk = *parsed
+	return nil
+}
+
+// MarshalText writes the proof in libsnark text format
+func (proof *Proof) MarshalText() ([]byte, error) {
+	if err := proof.validate(); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	writeG1Text(&buf, proof.A)
+	buf.WriteString(" ")
+	writeG1Text(&buf, proof.Ap)
+	buf.WriteString("\n")
+	writeG2Text(&buf, proof.B)
+	buf.WriteString(" ")
+	writeG1Text(&buf, proof.Bp)
+	buf.WriteString("\n")
+	writeG1Text(&buf, proof.C)
+	buf.WriteString(" ")
+	writeG1Text(&buf, proof.Cp)
+	buf.WriteString("\n")
+	writeG1Text(&buf, proof.H)
+	buf.WriteString("\n")
+	writeG1Text(&buf, proof.K)
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// UnmarshalText parses the proof in libsnark text format
+func (proof *Proof) UnmarshalText(text []byte) error {
+	parsed, err := newTokenizer(bytes.NewReader(text)).readProof()
+	if err != nil {
+		return err
+	}
+	*proof = *parsed
+	return nil
+}
+
+// binary format
+
+// binaryReader consumes data and remembers the first error
+type binaryReader struct {
+	data []byte
+	err  error
+}
+
+func (r *binaryReader) take(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.data) < n {
+		r.err = errShortData
+		return nil
+	}
+	result := r.data[:n]
+	r.data = r.data[n:]
+	return result
+}
+
+func (r *binaryReader) g1() *G1 {
+	data := r.take(g1Size)
+	if r.err != nil {
+		return nil
+	}
+	p, err := UnmarshalG1(data)
+	r.err = err
+	return p
+}
+
+func (r *binaryReader) g2() *G2 {
+	data := r.take(g2Size)
+	if r.err != nil {
+		return nil
+	}
+	p, err := UnmarshalG2(data)
+	r.err = err
+	return p
+}
+
+func (r *binaryReader) uint32() uint32 {
+	data := r.take(4)
+	if r.err != nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(data)
+}
+
+func (r *binaryReader) uint64() uint64 {
+	data := r.take(8)
+	if r.err != nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// finish reports the error or the trailing data
+func (r *binaryReader) finish() error {
+	if r.err == nil && len(r.data) != 0 {
+		return errors.New("Data is too long")
+	}
+	return r.err
+}
+
+func appendUint32(data []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	return append(data, buf[:]...)
+}
+
+func appendUint64(data []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	return append(data, buf[:]...)
+}
+
+// MarshalBinary encodes the key as A, B, C, Gamma, GammaBeta1, GammaBeta2, Z,
+// number of IC points and IC points
+func (vk *VerifyingKey) MarshalBinary() ([]byte, error) {
+	if err := vk.validate(); err != nil {
+		return nil, err
+	}
+	data := make([]byte, 0, 5*g2Size+2*g1Size+4+len(vk.IC)*g1Size)
+	data = append(data, vk.A.Marshal()...)
+	data = append(data, vk.B.Marshal()...)
+	data = append(data, vk.C.Marshal()...)
+	data = append(data, vk.Gamma.Marshal()...)
+	data = append(data, vk.GammaBeta1.Marshal()...)
+	data = append(data, vk.GammaBeta2.Marshal()...)
+	data = append(data, vk.Z.Marshal()...)
+	data = appendUint32(data, uint32(len(vk.IC)))
+	for _, p := range vk.IC {
+		data = append(data, p.Marshal()...)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes the key written by MarshalBinary
+func (vk *VerifyingKey) UnmarshalBinary(data []byte) error {
+	r := &binaryReader{data: data}
+	parsed := &VerifyingKey{
+		A:          r.g2(),
+		B:          r.g1(),
+		C:          r.g2(),
+		Gamma:      r.g2(),
+		GammaBeta1: r.g1(),
+		GammaBeta2: r.g2(),
+		Z:          r.g2(),
+	}
+	n := r.uint32()
+	if r.err == nil && uint64(n)*g1Size != uint64(len(r.data)) {
+		return errors.New("Invalid number of IC points")
+	}
+	parsed.IC = make([]*G1, 0, n)
+	for i := uint32(0); i < n && r.err == nil; i++ {
+		parsed.IC = append(parsed.IC, r.g1())
+	}
+	if err := r.finish(); err != nil {
+		return err
+	}
+	if err := parsed.validate(); err != nil {
+		return err
+	}
+	*vk = *parsed
+	return nil
+}
+
+// MarshalBinary encodes the key as VerifyingKey.MarshalBinary does,
+// but IC is written as the first point, domain size, number of values
+// and pairs of index and value
+func (vk *LibsnarkVerifyingKey) MarshalBinary() ([]byte, error) {
+	if vk.A == nil || vk.B == nil || vk.C == nil || vk.Gamma == nil || vk.GammaBeta1 == nil ||
+		vk.GammaBeta2 == nil || vk.Z == nil || vk.IC == nil || vk.IC.first == nil ||
+		len(vk.IC.indices) != len(vk.IC.rest) {
+		return nil, ErrInvalidVerifyingKey
+	}
+	data := make([]byte, 0, 5*g2Size+3*g1Size+12+len(vk.IC.rest)*(8+g1Size))
+	data = append(data, vk.A.Marshal()...)
+	data = append(data, vk.B.Marshal()...)
+	data = append(data, vk.C.Marshal()...)
+	data = append(data, vk.Gamma.Marshal()...)
+	data = append(data, vk.GammaBeta1.Marshal()...)
+	data = append(data, vk.GammaBeta2.Marshal()...)
+	data = append(data, vk.Z.Marshal()...)
+	data = append(data, vk.IC.first.Marshal()...)
+	data = appendUint64(data, vk.IC.domainSize)
+	data = appendUint32(data, uint32(len(vk.IC.rest)))
+	for i, p := range vk.IC.rest {
+		data = appendUint64(data, vk.IC.indices[i])
+		data = append(data, p.Marshal()...)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes the key written by MarshalBinary
+func (vk *LibsnarkVerifyingKey) UnmarshalBinary(data []byte) error {
+	r := &binaryReader{data: data}
+	parsed := &LibsnarkVerifyingKey{
+		A:          r.g2(),
+		B:          r.g1(),
+		C:          r.g2(),
+		Gamma:      r.g2(),
+		GammaBeta1: r.g1(),
+		GammaBeta2: r.g2(),
+		Z:          r.g2(),
+		IC:         &SparseVector{first: r.g1()},
+	}
+	parsed.IC.domainSize = r.uint64()
+	n := r.uint32()
+	if r.err == nil && uint64(n)*(8+g1Size) != uint64(len(r.data)) {
+		return errors.New("Invalid number of IC points")
+	}
+	if r.err == nil && (parsed.IC.domainSize > maxDomainSize || uint64(n) > parsed.IC.domainSize) {
+		return errors.New("Invalid domain size")
+	}
+	for i := uint32(0); i < n && r.err == nil; i++ {
+		index := r.uint64()
+		if r.err == nil && (index >= parsed.IC.domainSize ||
+			(i != 0 && index <= parsed.IC.indices[i-1])) {
+			return errors.New("Invalid IC index")
+		}
+		parsed.IC.indices = append(parsed.IC.indices, index)
+		parsed.IC.rest = append(parsed.IC.rest, r.g1())
+	}
+	if err := r.finish(); err != nil {
+		return err
+	}
+	*vk = *parsed
+	return nil
+}
+
+// MarshalBinary encodes the proof as A, Ap, B, Bp, C, Cp, H, K
+func (proof *Proof) MarshalBinary() ([]byte, error) {
+	if err := proof.validate(); err != nil {
+		return nil, err
+	}
+	data := make([]byte, 0, 7*g1Size+g2Size)
+	data = append(data, proof.A.Marshal()...)
+	data = append(data, proof.Ap.Marshal()...)
+	data = append(data, proof.B.Marshal()...)
+	data = append(data, proof.Bp.Marshal()...)
+	data = append(data, proof.C.Marshal()...)
+	data = append(data, proof.Cp.Marshal()...)
+	data = append(data, proof.H.Marshal()...)
+	data = append(data, proof.K.Marshal()...)
+	return data, nil
+}
+
+// UnmarshalBinary decodes the proof written by MarshalBinary
+func (proof *Proof) UnmarshalBinary(data []byte) error {
+	r := &binaryReader{data: data}
+	parsed := &Proof{
+		A:  r.g1(),
+		Ap: r.g1(),
+		B:  r.g2(),
+		Bp: r.g1(),
+		C:  r.g1(),
+		Cp: r.g1(),
+		H:  r.g1(),
+		K:  r.g1(),
+	}
+	if err := r.finish(); err != nil {
+		return err
+	}
+	*proof = *parsed
+	return nil
+}
+
+// JSON format
+
+type jsonG1 [2]string
+
+type jsonG2 [2][2]string
+
+func toHex(data []byte) string {
+	return fmt.Sprintf("0x%064x", data)
+}
+
+func g1ToJSON(p *G1) jsonG1 {
+	marshalled := p.Marshal()
+	return jsonG1{toHex(marshalled[:32]), toHex(marshalled[32:])}
+}
+
+func g1FromJSON(p jsonG1) (*G1, error) {
+	return NewG1FromStrings(p[0], p[1], 16)
+}
+
+func g2ToJSON(p *G2) jsonG2 {
+	marshalled := p.Marshal()
+	return jsonG2{
+		{toHex(marshalled[:32]), toHex(marshalled[32:64])},
+		{toHex(marshalled[64:96]), toHex(marshalled[96:])},
+	}
+}
+
+func g2FromJSON(p jsonG2) (*G2, error) {
+	return NewG2FromStrings(p[0], p[1], 16)
+}
+
+func g1SliceToJSON(points []*G1) []jsonG1 {
+	result := make([]jsonG1, len(points))
+	for i, p := range points {
+		result[i] = g1ToJSON(p)
+	}
+	return result
+}
+
+func g1SliceFromJSON(points []jsonG1) ([]*G1, error) {
+	result := make([]*G1, len(points))
+	for i, p := range points {
+		point, err := g1FromJSON(p)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = point
+	}
+	return result, nil
+}
+
+type verifyingKeyJSON struct {
+	A          jsonG2   `json:"a"`
+	B          jsonG1   `json:"b"`
+	C          jsonG2   `json:"c"`
+	Gamma      jsonG2   `json:"gamma"`
+	GammaBeta1 jsonG1   `json:"gamma_beta_1"`
+	GammaBeta2 jsonG2   `json:"gamma_beta_2"`
+	Z          jsonG2   `json:"z"`
+	IC         []jsonG1 `json:"ic"`
+}
+
+type sparseVectorJSON struct {
+	First      jsonG1   `json:"first"`
+	DomainSize uint64   `json:"domain_size"`
+	Indices    []uint64 `json:"indices"`
+	Values     []jsonG1 `json:"values"`
+}
+
+type libsnarkVerifyingKeyJSON struct {
+	A          jsonG2           `json:"a"`
+	B          jsonG1           `json:"b"`
+	C          jsonG2           `json:"c"`
+	Gamma      jsonG2           `json:"gamma"`
+	GammaBeta1 jsonG1           `json:"gamma_beta_1"`
+	GammaBeta2 jsonG2           `json:"gamma_beta_2"`
+	Z          jsonG2           `json:"z"`
+	IC         sparseVectorJSON `json:"ic"`
+}
+
+type proofJSON struct {
+	A  jsonG1 `json:"a"`
+	Ap jsonG1 `json:"a_p"`
+	B  jsonG2 `json:"b"`
+	Bp jsonG1 `json:"b_p"`
+	C  jsonG1 `json:"c"`
+	Cp jsonG1 `json:"c_p"`
+	H  jsonG1 `json:"h"`
+	K  jsonG1 `json:"k"`
+}
+
+// jsonPoints parses G1 and G2 points in order, stopping at the first error
+type jsonPoints struct {
+	err error
+}
+
+func (j *jsonPoints) g1(p jsonG1) *G1 {
+	if j.err != nil {
+		return nil
+	}
+	point, err := g1FromJSON(p)
+	j.err = err
+	return point
+}
+
+func (j *jsonPoints) g2(p jsonG2) *G2 {
+	if j.err != nil {
+		return nil
+	}
+	point, err := g2FromJSON(p)
+	j.err = err
+	return point
+}
+
+// MarshalJSON writes the key with hex coordinates
+func (vk *VerifyingKey) MarshalJSON() ([]byte, error) {
+	if err := vk.validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(verifyingKeyJSON{
+		A:          g2ToJSON(vk.A),
+		B:          g1ToJSON(vk.B),
+		C:          g2ToJSON(vk.C),
+		Gamma:      g2ToJSON(vk.Gamma),
+		GammaBeta1: g1ToJSON(vk.GammaBeta1),
+		GammaBeta2: g2ToJSON(vk.GammaBeta2),
+		Z:          g2ToJSON(vk.Z),
+		IC:         g1SliceToJSON(vk.IC),
+	})
+}
+
+// UnmarshalJSON parses the key written by MarshalJSON
+func (vk *VerifyingKey) UnmarshalJSON(data []byte) error {
+	var decoded verifyingKeyJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	j := &jsonPoints{}
+	parsed := &VerifyingKey{
+		A:          j.g2(decoded.A),
+		B:          j.g1(decoded.B),
+		C:          j.g2(decoded.C),
+		Gamma:      j.g2(decoded.Gamma),
+		GammaBeta1: j.g1(decoded.GammaBeta1),
+		GammaBeta2: j.g2(decoded.GammaBeta2),
+		Z:          j.g2(decoded.Z),
+	}
+	if j.err != nil {
+		return j.err
+	}
+	ic, err := g1SliceFromJSON(decoded.IC)
+	if err != nil {
+		return err
+	}
+	parsed.IC = ic
+	if err := parsed.validate(); err != nil {
+		return err
+	}
+	*vk = *parsed
+	return nil
+}
+
+// MarshalJSON writes the key with hex coordinates and sparse IC
+func (vk *LibsnarkVerifyingKey) MarshalJSON() ([]byte, error) {
+	if vk.A == nil || vk.B == nil || vk.C == nil || vk.Gamma == nil || vk.GammaBeta1 == nil ||
+		vk.GammaBeta2 == nil || vk.Z == nil || vk.IC == nil || vk.IC.first == nil {
+		return nil, ErrInvalidVerifyingKey
+	}
+	return json.Marshal(libsnarkVerifyingKeyJSON{
+		A:          g2ToJSON(vk.A),
+		B:          g1ToJSON(vk.B),
+		C:          g2ToJSON(vk.C),
+		Gamma:      g2ToJSON(vk.Gamma),
+		GammaBeta1: g1ToJSON(vk.GammaBeta1),
+		GammaBeta2: g2ToJSON(vk.GammaBeta2),
+		Z:          g2ToJSON(vk.Z),
+		IC: sparseVectorJSON{
+			First:      g1ToJSON(vk.IC.first),
+			DomainSize: vk.IC.domainSize,
+			Indices:    append([]uint64{}, vk.IC.indices...),
+			Values:     g1SliceToJSON(vk.IC.rest),
+		},
+	})
+}
+
+// UnmarshalJSON parses the key written by MarshalJSON
+func (vk *LibsnarkVerifyingKey) UnmarshalJSON(data []byte) error {
+	var decoded libsnarkVerifyingKeyJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	j := &jsonPoints{}
+	parsed := &LibsnarkVerifyingKey{
+		A:          j.g2(decoded.A),
+		B:          j.g1(decoded.B),
+		C:          j.g2(decoded.C),
+		Gamma:      j.g2(decoded.Gamma),
+		GammaBeta1: j.g1(decoded.GammaBeta1),
+		GammaBeta2: j.g2(decoded.GammaBeta2),
+		Z:          j.g2(decoded.Z),
+		IC:         &SparseVector{first: j.g1(decoded.IC.First), domainSize: decoded.IC.DomainSize},
+	}
+	if j.err != nil {
+		return j.err
+	}
+	if decoded.IC.DomainSize > maxDomainSize || len(decoded.IC.Indices) != len(decoded.IC.Values) {
+		return errors.New("Invalid IC")
+	}
+	for i, index := range decoded.IC.Indices {
+		if index >= decoded.IC.DomainSize || (i != 0 && index <= decoded.IC.Indices[i-1]) {
+			return errors.New("Invalid IC index")
+		}
+	}
+	values, err := g1SliceFromJSON(decoded.IC.Values)
+	if err != nil {
+		return err
+	}
+	parsed.IC.indices = append([]uint64{}, decoded.IC.Indices...)
+	parsed.IC.rest = values
+	*vk = *parsed
+	return nil
+}
+
+// MarshalJSON writes the proof with hex coordinates
+func (proof *Proof) MarshalJSON() ([]byte, error) {
+	if err := proof.validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(proofJSON{
+		A:  g1ToJSON(proof.A),
+		Ap: g1ToJSON(proof.Ap),
+		B:  g2ToJSON(proof.B),
+		Bp: g1ToJSON(proof.Bp),
+		C:  g1ToJSON(proof.C),
+		Cp: g1ToJSON(proof.Cp),
+		H:  g1ToJSON(proof.H),
+		K:  g1ToJSON(proof.K),
+	})
+}
+
+// UnmarshalJSON parses the proof written by MarshalJSON
+func (proof *Proof) UnmarshalJSON(data []byte) error {
+	var decoded proofJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	j := &jsonPoints{}
+	parsed := &Proof{
+		A:  j.g1(decoded.A),
+		Ap: j.g1(decoded.Ap),
+		B:  j.g2(decoded.B),
+		Bp: j.g1(decoded.Bp),
+		C:  j.g1(decoded.C),
+		Cp: j.g1(decoded.Cp),
+		H:  j.g1(decoded.H),
+		K:  j.g1(decoded.K),
+	}
+	if j.err != nil {
+		return j.err
+	}
+	*proof = *parsed
+	return nil
+}
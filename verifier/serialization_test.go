@@ -0,0 +1,133 @@
+package verifier
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+type marshaller interface {
+	encoding.TextMarshaler
+	encoding.BinaryMarshaler
+	json.Marshaler
+}
+
+// roundTrip checks that every format reads back into the same value
+func roundTrip(t *testing.T, original marshaller, makeEmpty func() interface{}) {
+	text, err := original.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromText := makeEmpty()
+	if err := fromText.(encoding.TextUnmarshaler).UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+
+	binary, err := original.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromBinary := makeEmpty()
+	if err := fromBinary.(encoding.BinaryUnmarshaler).UnmarshalBinary(binary); err != nil {
+		t.Fatal(err)
+	}
+
+	js, err := json.Marshal(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromJSON := makeEmpty()
+	if err := json.Unmarshal(js, fromJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, decoded := range map[string]interface{}{"text": fromText, "binary": fromBinary, "JSON": fromJSON} {
+		binaryAgain, err := decoded.(marshaller).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(binary, binaryAgain) {
+			t.Fatalf("%s round trip has changed the value", name)
+		}
+		textAgain, err := decoded.(marshaller).MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(text, textAgain) {
+			t.Fatalf("%s round trip has changed the text", name)
+		}
+	}
+
+	// truncated binary data is an error, not a panic
+	for i := 0; i < len(binary); i += 17 {
+		if err := makeEmpty().(encoding.BinaryUnmarshaler).UnmarshalBinary(binary[:i]); err == nil {
+			t.Fatalf("truncated data of length %d is accepted", i)
+		}
+	}
+}
+
+func TestVerifyingKeySerialization(t *testing.T) {
+	vk, proof, witness := zokratesExample(t)
+	roundTrip(t, vk, func() interface{} { return new(VerifyingKey) })
+	roundTrip(t, proof, func() interface{} { return new(Proof) })
+
+	// deserialized values still verify
+	js, _ := json.Marshal(vk)
+	decodedVK := new(VerifyingKey)
+	json.Unmarshal(js, decodedVK)
+	js, _ = json.Marshal(proof)
+	decodedProof := new(Proof)
+	json.Unmarshal(js, decodedProof)
+	if err := Verify(decodedVK, decodedProof, witness); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLibsnarkVerifyingKeySerialization(t *testing.T) {
+	vk := new(LibsnarkVerifyingKey)
+	if err := vk.ParseFromFile("verificationKey.txt"); err != nil {
+		t.Fatal(err)
+	}
+	roundTrip(t, vk, func() interface{} { return new(LibsnarkVerifyingKey) })
+
+	// sparse IC with a gap and a point at infinity
+	vk.IC = &SparseVector{
+		first:      new(G1).ScalarBaseMult(big.NewInt(0)),
+		domainSize: 3,
+		indices:    []uint64{0, 2},
+		rest:       []*G1{GetG1Base(), new(G1).ScalarBaseMult(big.NewInt(5))},
+	}
+	roundTrip(t, vk, func() interface{} { return new(LibsnarkVerifyingKey) })
+	js, _ := json.Marshal(vk)
+	decoded := new(LibsnarkVerifyingKey)
+	json.Unmarshal(js, decoded)
+	if !reflect.DeepEqual(decoded.IC.indices, vk.IC.indices) || decoded.IC.domainSize != 3 {
+		t.Fatal("sparse indices are lost")
+	}
+}
+
+func TestProofTextMatchesLibsnark(t *testing.T) {
+	content, err := ioutil.ReadFile("../proof.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof := new(Proof)
+	if err := proof.UnmarshalText(content); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseProofFromString(string(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(proof, parsed) {
+		t.Fatal("proof is parsed differently")
+	}
+	text, _ := proof.MarshalText()
+	if !bytes.Equal(bytes.TrimSpace(text), bytes.TrimSpace(content)) {
+		t.Fatalf("proof is written differently:\n%s\n%s", text, content)
+	}
+}
@@ -0,0 +1,202 @@
+package verifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// snarkjs writes Groth16 keys and proofs as JSON with decimal coordinates
+// in Jacobian form. G1 point is [x, y, z], G2 point is
+// [[x.c0, x.c1], [y.c0, y.c1], [z.c0, z.c1]], the affine point is
+// (x/z^2, y/z^3) and z = 0 stands for the point at infinity.
+// public.json is an array of decimal strings
+
+type snarkjsG1 []string
+
+type snarkjsG2 [][]string
+
+type snarkjsVerifyingKeyJSON struct {
+	Protocol string      `json:"protocol"`
+	Curve    string      `json:"curve"`
+	NPublic  *int        `json:"nPublic"`
+	Alpha    snarkjsG1   `json:"vk_alpha_1"`
+	Beta     snarkjsG2   `json:"vk_beta_2"`
+	Gamma    snarkjsG2   `json:"vk_gamma_2"`
+	Delta    snarkjsG2   `json:"vk_delta_2"`
+	IC       []snarkjsG1 `json:"IC"`
+}
+
+type snarkjsProofJSON struct {
+	Protocol string    `json:"protocol"`
+	Curve    string    `json:"curve"`
+	A        snarkjsG1 `json:"pi_a"`
+	B        snarkjsG2 `json:"pi_b"`
+	C        snarkjsG1 `json:"pi_c"`
+}
+
+// checkSnarkjsHeader rejects files made for other proving systems or curves.
+// Both fields are optional, as older snarkjs versions do not write them
+func checkSnarkjsHeader(protocol, curve string) error {
+	if protocol != "" && protocol != "groth16" {
+		return fmt.Errorf("Unsupported protocol %q", protocol)
+	}
+	if curve != "" && curve != "bn128" && curve != "bn254" {
+		return fmt.Errorf("Unsupported curve %q", curve)
+	}
+	return nil
+}
+
+func snarkjsCoordinate(s string) (*big.Int, error) {
+	n, err := base10bi(s)
+	if err != nil {
+		return nil, err
+	}
+	if n.Sign() < 0 || n.Cmp(P) >= 0 {
+		return nil, ErrCoordinateOutOfRange
+	}
+	return n, nil
+}
+
+func snarkjsToG1(p snarkjsG1) (*G1, error) {
+	if len(p) != 3 {
+		return nil, fmt.Errorf("G1 point should have 3 coordinates, got %d", len(p))
+	}
+	coordinates := make([]*big.Int, 3)
+	for i, s := range p {
+		c, err := snarkjsCoordinate(s)
+		if err != nil {
+			return nil, err
+		}
+		coordinates[i] = c
+	}
+	x, y, z := coordinates[0], coordinates[1], coordinates[2]
+	if z.Sign() == 0 {
+		return new(G1).ScalarBaseMult(big.NewInt(0)), nil
+	}
+	zInv := new(big.Int).ModInverse(z, P)
+	zInv2 := new(big.Int).Mul(zInv, zInv)
+	x.Mul(x, zInv2).Mod(x, P)
+	y.Mul(y, zInv2).Mul(y, zInv).Mod(y, P)
+	return NewG1(x, y)
+}
+
+func snarkjsToG2(p snarkjsG2) (*G2, error) {
+	if len(p) != 3 {
+		return nil, fmt.Errorf("G2 point should have 3 coordinates, got %d", len(p))
+	}
+	coordinates := make([]fp2, 3)
+	for i, element := range p {
+		if len(element) != 2 {
+			return nil, fmt.Errorf("G2 coordinate should have 2 elements, got %d", len(element))
+		}
+		for j, s := range element {
+			c, err := snarkjsCoordinate(s)
+			if err != nil {
+				return nil, err
+			}
+			coordinates[i][j] = c
+		}
+	}
+	x, y, z := coordinates[0], coordinates[1], coordinates[2]
+	if z.isZero() {
+		return new(G2).ScalarBaseMult(big.NewInt(0)), nil
+	}
+	zInv := z.inverse()
+	zInv2 := zInv.mul(zInv)
+	x = x.mul(zInv2)
+	y = y.mul(zInv2).mul(zInv)
+	// snarkjs writes Fp2 elements as c0 c1, while bn256 expects c1 c0
+	return NewG2([2]*big.Int{x[1], x[0]}, [2]*big.Int{y[1], y[0]})
+}
+
+// snarkjsPoints converts points in order, stopping at the first error
+type snarkjsPoints struct {
+	err error
+}
+
+func (s *snarkjsPoints) g1(name string, p snarkjsG1) *G1 {
+	if s.err != nil {
+		return nil
+	}
+	point, err := snarkjsToG1(p)
+	if err != nil {
+		s.err = fmt.Errorf("Invalid %s: %w", name, err)
+	}
+	return point
+}
+
+func (s *snarkjsPoints) g2(name string, p snarkjsG2) *G2 {
+	if s.err != nil {
+		return nil
+	}
+	point, err := snarkjsToG2(p)
+	if err != nil {
+		s.err = fmt.Errorf("Invalid %s: %w", name, err)
+	}
+	return point
+}
+
+// ParseSnarkjsVerifyingKey parses Groth16 verification_key.json produced by snarkjs
+func ParseSnarkjsVerifyingKey(r io.Reader) (*Groth16VerifyingKey, error) {
+	var decoded snarkjsVerifyingKeyJSON
+	if err := json.NewDecoder(r).Decode(&decoded); err != nil {
+		return nil, err
+	}
+	if err := checkSnarkjsHeader(decoded.Protocol, decoded.Curve); err != nil {
+		return nil, err
+	}
+	if len(decoded.IC) == 0 || len(decoded.IC) > maxDomainSize+1 {
+		return nil, fmt.Errorf("Invalid number of IC points %d", len(decoded.IC))
+	}
+	if decoded.NPublic != nil && *decoded.NPublic+1 != len(decoded.IC) {
+		return nil, fmt.Errorf("%d IC points for %d public inputs", len(decoded.IC), *decoded.NPublic)
+	}
+
+	s := &snarkjsPoints{}
+	vk := &Groth16VerifyingKey{
+		Alpha: s.g1("vk_alpha_1", decoded.Alpha),
+		Beta:  s.g2("vk_beta_2", decoded.Beta),
+		Gamma: s.g2("vk_gamma_2", decoded.Gamma),
+		Delta: s.g2("vk_delta_2", decoded.Delta),
+		IC:    make([]*G1, len(decoded.IC)),
+	}
+	for i, p := range decoded.IC {
+		vk.IC[i] = s.g1(fmt.Sprintf("IC[%d]", i), p)
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return vk, nil
+}
+
+// ParseSnarkjsProof parses Groth16 proof.json produced by snarkjs
+func ParseSnarkjsProof(r io.Reader) (*Groth16Proof, error) {
+	var decoded snarkjsProofJSON
+	if err := json.NewDecoder(r).Decode(&decoded); err != nil {
+		return nil, err
+	}
+	if err := checkSnarkjsHeader(decoded.Protocol, decoded.Curve); err != nil {
+		return nil, err
+	}
+	s := &snarkjsPoints{}
+	proof := &Groth16Proof{
+		A: s.g1("pi_a", decoded.A),
+		B: s.g2("pi_b", decoded.B),
+		C: s.g1("pi_c", decoded.C),
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return proof, nil
+}
+
+// ParseSnarkjsPublicInputs parses public.json produced by snarkjs
+func ParseSnarkjsPublicInputs(r io.Reader) (Witness, error) {
+	var inputs []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&inputs); err != nil {
+		return nil, err
+	}
+	return parseJSONInputs(inputs)
+}
@@ -0,0 +1,102 @@
+package verifier
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// snarkjs representation of the points with random z, so that
+// the parser has to normalise them
+func jacobianG1(t *testing.T, p *G1) []string {
+	m := p.Marshal()
+	x, y := new(big.Int).SetBytes(m[:32]), new(big.Int).SetBytes(m[32:])
+	z := randomScalar(t)
+	z2 := new(big.Int).Mul(z, z)
+	x.Mul(x, z2).Mod(x, P)
+	y.Mul(y, z2).Mul(y, z).Mod(y, P)
+	return []string{x.String(), y.String(), z.String()}
+}
+
+func jacobianG2(t *testing.T, p *G2) [][]string {
+	m := p.Marshal()
+	word := func(i int) *big.Int { return new(big.Int).SetBytes(m[i*32 : (i+1)*32]) }
+	x, y := fp2{word(1), word(0)}, fp2{word(3), word(2)}
+	z := fp2{randomScalar(t), randomScalar(t)}
+	z2 := z.mul(z)
+	x = x.mul(z2)
+	y = y.mul(z2).mul(z)
+	return [][]string{
+		{x[0].String(), x[1].String()},
+		{y[0].String(), y[1].String()},
+		{z[0].String(), z[1].String()},
+	}
+}
+
+func TestSnarkjsImport(t *testing.T) {
+	witness := Witness{big.NewInt(3), big.NewInt(0)}
+	vk, proof := groth16Example(t, witness)
+	// witness element is zero, so IC point does not matter
+	vk.IC[2] = new(G1).ScalarBaseMult(big.NewInt(0))
+
+	ic := [][]string{jacobianG1(t, vk.IC[0]), jacobianG1(t, vk.IC[1]), {"0", "1", "0"}}
+	keyJSON, _ := json.Marshal(map[string]interface{}{
+		"protocol":   "groth16",
+		"curve":      "bn128",
+		"nPublic":    2,
+		"vk_alpha_1": jacobianG1(t, vk.Alpha),
+		"vk_beta_2":  jacobianG2(t, vk.Beta),
+		"vk_gamma_2": jacobianG2(t, vk.Gamma),
+		"vk_delta_2": jacobianG2(t, vk.Delta),
+		"IC":         ic,
+	})
+	parsedVK, err := ParseSnarkjsVerifyingKey(strings.NewReader(string(keyJSON)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proofJSON, _ := json.Marshal(map[string]interface{}{
+		"pi_a":     jacobianG1(t, proof.A),
+		"pi_b":     jacobianG2(t, proof.B),
+		"pi_c":     jacobianG1(t, proof.C),
+		"protocol": "groth16",
+	})
+	parsedProof, err := ParseSnarkjsProof(strings.NewReader(string(proofJSON)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputs, err := ParseSnarkjsPublicInputs(strings.NewReader(`["3", "0"]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyGroth16(parsedVK, parsedProof, inputs); err != nil {
+		t.Fatal(err)
+	}
+	inputs[0] = big.NewInt(4)
+	if err := VerifyGroth16(parsedVK, parsedProof, inputs); err != ErrGroth16Check {
+		t.Fatalf("expected %v, got %v", ErrGroth16Check, err)
+	}
+}
+
+func TestSnarkjsErrors(t *testing.T) {
+	for _, input := range []string{
+		`{"protocol": "plonk", "pi_a": ["1", "2", "1"]}`,
+		`{"curve": "bls12381", "pi_a": ["1", "2", "1"]}`,
+		`{"pi_a": ["1", "2"]}`,
+		`{"pi_a": ["1", "3", "1"], "pi_b": [["0", "0"], ["1", "0"], ["0", "0"]], "pi_c": ["1", "2", "1"]}`,
+		`{"pi_a": ["1", "-2", "1"], "pi_b": [["0", "0"], ["1", "0"], ["0", "0"]], "pi_c": ["1", "2", "1"]}`,
+		`{"pi_a": ["1", "2", "1"], "pi_b": [["0", "0"], ["1", "0"]], "pi_c": ["1", "2", "1"]}`,
+	} {
+		if _, err := ParseSnarkjsProof(strings.NewReader(input)); err == nil {
+			t.Fatalf("%s is accepted", input)
+		}
+	}
+
+	// nPublic should match the number of IC points
+	_, err := ParseSnarkjsVerifyingKey(strings.NewReader(`{"nPublic": 1, "IC": [["1", "2", "1"]]}`))
+	if err == nil {
+		t.Fatal("IC of a wrong length is accepted")
+	}
+}
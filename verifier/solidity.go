@@ -0,0 +1,277 @@
+package verifier
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"text/template"
+)
+
+// Solidity verifier checks the same five equations as naiveSplitVerification
+// with EIP-196 (ecAdd, ecMul) and EIP-197 (ecPairing) precompiles.
+// Points are embedded in the precompile layout, which is also the layout of
+// bn256 Marshal: G1 is (x, y) and G2 is (x.c1, x.c0, y.c1, y.c0)
+
+var solidityIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+type solidityVerifierData struct {
+	ContractName string
+	FieldModulus string
+	ScalarField  string
+	Generator    jsonG2
+	A            jsonG2
+	B            jsonG1
+	C            jsonG2
+	Gamma        jsonG2
+	GammaBeta1   jsonG1
+	GammaBeta2   jsonG2
+	Z            jsonG2
+	IC           []jsonG1
+	Inputs       int
+}
+
+// WriteSolidityVerifier writes a Solidity contract with the given name that
+// verifies proofs against the key. verifyProof returns true for a valid proof,
+// verify returns 0 or the number of the failed equation, as in the server response
+func WriteSolidityVerifier(w io.Writer, vk *VerifyingKey, contractName string) error {
+	if err := vk.validate(); err != nil {
+		return err
+	}
+	if !solidityIdentifier.MatchString(contractName) {
+		return errors.New("Contract name is not a valid identifier")
+	}
+	data := solidityVerifierData{
+		ContractName: contractName,
+		FieldModulus: fmt.Sprintf("0x%064x", P),
+		ScalarField:  fmt.Sprintf("0x%064x", Order),
+		Generator:    g2ToJSON(GetG2Base()),
+		A:            g2ToJSON(vk.A),
+		B:            g1ToJSON(vk.B),
+		C:            g2ToJSON(vk.C),
+		Gamma:        g2ToJSON(vk.Gamma),
+		GammaBeta1:   g1ToJSON(vk.GammaBeta1),
+		GammaBeta2:   g2ToJSON(vk.GammaBeta2),
+		Z:            g2ToJSON(vk.Z),
+		IC:           g1SliceToJSON(vk.IC),
+		Inputs:       len(vk.IC) - 1,
+	}
+	return solidityVerifierTemplate.Execute(w, data)
+}
+
+var solidityVerifierTemplate = template.Must(template.New("verifier").Parse(
+	`{{define "g1"}}Pairing.G1Point(uint256({{index . 0}}), {{index . 1}}){{end -}}
+{{define "g2"}}Pairing.G2Point(
+            [uint256({{index . 0 0}}), {{index . 0 1}}],
+            [uint256({{index . 1 0}}), {{index . 1 1}}]
+        ){{end -}}
+// SPDX-License-Identifier: UNLICENSED
+// This file is generated from a verifying key, do not edit it by hand
+pragma solidity ^0.8.0;
+
+library Pairing {
+    uint256 internal constant FIELD_MODULUS = {{.FieldModulus}};
+
+    struct G1Point {
+        uint256 X;
+        uint256 Y;
+    }
+
+    // Fp2 elements are encoded as [c1, c0]
+    struct G2Point {
+        uint256[2] X;
+        uint256[2] Y;
+    }
+
+    // P2 is the generator of G2
+    function P2() internal pure returns (G2Point memory) {
+        return {{template "g2" .Generator}};
+    }
+
+    function negate(G1Point memory p) internal pure returns (G1Point memory) {
+        if (p.X == 0 && p.Y == 0) {
+            return G1Point(0, 0);
+        }
+        return G1Point(p.X, FIELD_MODULUS - (p.Y % FIELD_MODULUS));
+    }
+
+    function addition(G1Point memory p1, G1Point memory p2) internal view returns (G1Point memory r) {
+        uint256[4] memory input = [p1.X, p1.Y, p2.X, p2.Y];
+        bool success;
+        assembly {
+            success := staticcall(gas(), 6, input, 0x80, r, 0x40)
+        }
+        require(success, "Pairing: ecAdd has failed");
+    }
+
+    function scalarMul(G1Point memory p, uint256 s) internal view returns (G1Point memory r) {
+        uint256[3] memory input = [p.X, p.Y, s];
+        bool success;
+        assembly {
+            success := staticcall(gas(), 7, input, 0x60, r, 0x40)
+        }
+        require(success, "Pairing: ecMul has failed");
+    }
+
+    // pairing checks e(p1[0], p2[0]) * ... * e(p1[n], p2[n]) == 1
+    function pairing(G1Point[] memory p1, G2Point[] memory p2) internal view returns (bool) {
+        require(p1.length == p2.length, "Pairing: lengths differ");
+        uint256 inputSize = p1.length * 6;
+        uint256[] memory input = new uint256[](inputSize);
+        for (uint256 i = 0; i < p1.length; i++) {
+            input[i * 6 + 0] = p1[i].X;
+            input[i * 6 + 1] = p1[i].Y;
+            input[i * 6 + 2] = p2[i].X[0];
+            input[i * 6 + 3] = p2[i].X[1];
+            input[i * 6 + 4] = p2[i].Y[0];
+            input[i * 6 + 5] = p2[i].Y[1];
+        }
+        uint256[1] memory out;
+        bool success;
+        assembly {
+            success := staticcall(gas(), 8, add(input, 0x20), mul(inputSize, 0x20), out, 0x20)
+        }
+        require(success, "Pairing: ecPairing has failed");
+        return out[0] != 0;
+    }
+
+    function pairing2(
+        G1Point memory a1, G2Point memory a2,
+        G1Point memory b1, G2Point memory b2
+    ) internal view returns (bool) {
+        G1Point[] memory p1 = new G1Point[](2);
+        G2Point[] memory p2 = new G2Point[](2);
+        p1[0] = a1;
+        p1[1] = b1;
+        p2[0] = a2;
+        p2[1] = b2;
+        return pairing(p1, p2);
+    }
+
+    function pairing3(
+        G1Point memory a1, G2Point memory a2,
+        G1Point memory b1, G2Point memory b2,
+        G1Point memory c1, G2Point memory c2
+    ) internal view returns (bool) {
+        G1Point[] memory p1 = new G1Point[](3);
+        G2Point[] memory p2 = new G2Point[](3);
+        p1[0] = a1;
+        p1[1] = b1;
+        p1[2] = c1;
+        p2[0] = a2;
+        p2[1] = b2;
+        p2[2] = c2;
+        return pairing(p1, p2);
+    }
+}
+
+contract {{.ContractName}} {
+    uint256 internal constant SCALAR_FIELD = {{.ScalarField}};
+
+    struct VerifyingKey {
+        Pairing.G2Point A;
+        Pairing.G1Point B;
+        Pairing.G2Point C;
+        Pairing.G2Point Gamma;
+        Pairing.G1Point GammaBeta1;
+        Pairing.G2Point GammaBeta2;
+        Pairing.G2Point Z;
+        Pairing.G1Point[] IC;
+    }
+
+    struct Proof {
+        Pairing.G1Point A;
+        Pairing.G1Point Ap;
+        Pairing.G2Point B;
+        Pairing.G1Point Bp;
+        Pairing.G1Point C;
+        Pairing.G1Point Cp;
+        Pairing.G1Point H;
+        Pairing.G1Point K;
+    }
+
+    function verifyingKey() internal pure returns (VerifyingKey memory vk) {
+        vk.A = {{template "g2" .A}};
+        vk.B = {{template "g1" .B}};
+        vk.C = {{template "g2" .C}};
+        vk.Gamma = {{template "g2" .Gamma}};
+        vk.GammaBeta1 = {{template "g1" .GammaBeta1}};
+        vk.GammaBeta2 = {{template "g2" .GammaBeta2}};
+        vk.Z = {{template "g2" .Z}};
+        vk.IC = new Pairing.G1Point[]({{len .IC}});
+{{- range $i, $p := .IC}}
+        vk.IC[{{$i}}] = {{template "g1" $p}};
+{{- end}}
+    }
+
+    // verify returns 0 for a valid proof and the number of the failed equation otherwise
+    function verify(uint256[] memory input, Proof memory proof) internal view returns (uint256) {
+        VerifyingKey memory vk = verifyingKey();
+        require(input.length + 1 == vk.IC.length, "Invalid length of the witness");
+        Pairing.G1Point memory accumulator = vk.IC[0];
+        for (uint256 i = 0; i < input.length; i++) {
+            require(input[i] < SCALAR_FIELD, "Witness element is out of the field range");
+            accumulator = Pairing.addition(accumulator, Pairing.scalarMul(vk.IC[i + 1], input[i]));
+        }
+        // e(proof.A, vk.A) == e(proof.Ap, P2)
+        if (!Pairing.pairing2(proof.A, vk.A, Pairing.negate(proof.Ap), Pairing.P2())) {
+            return 1;
+        }
+        // e(vk.B, proof.B) == e(proof.Bp, P2)
+        if (!Pairing.pairing2(vk.B, proof.B, Pairing.negate(proof.Bp), Pairing.P2())) {
+            return 2;
+        }
+        // e(proof.C, vk.C) == e(proof.Cp, P2)
+        if (!Pairing.pairing2(proof.C, vk.C, Pairing.negate(proof.Cp), Pairing.P2())) {
+            return 3;
+        }
+        // e(proof.K, vk.Gamma) == e(accumulator + proof.A + proof.C, vk.GammaBeta2) * e(vk.GammaBeta1, proof.B)
+        Pairing.G1Point memory t = Pairing.addition(Pairing.addition(accumulator, proof.A), proof.C);
+        if (!Pairing.pairing3(
+            proof.K, vk.Gamma,
+            Pairing.negate(t), vk.GammaBeta2,
+            Pairing.negate(vk.GammaBeta1), proof.B
+        )) {
+            return 4;
+        }
+        // e(accumulator + proof.A, proof.B) == e(proof.H, vk.Z) * e(proof.C, P2)
+        if (!Pairing.pairing3(
+            Pairing.addition(accumulator, proof.A), proof.B,
+            Pairing.negate(proof.H), vk.Z,
+            Pairing.negate(proof.C), Pairing.P2()
+        )) {
+            return 5;
+        }
+        return 0;
+    }
+
+    function verifyProof(
+        uint256[2] memory a,
+        uint256[2] memory a_p,
+        uint256[2][2] memory b,
+        uint256[2] memory b_p,
+        uint256[2] memory c,
+        uint256[2] memory c_p,
+        uint256[2] memory h,
+        uint256[2] memory k{{if .Inputs}},
+        uint256[{{.Inputs}}] memory input{{end}}
+    ) public view returns (bool) {
+        Proof memory proof;
+        proof.A = Pairing.G1Point(a[0], a[1]);
+        proof.Ap = Pairing.G1Point(a_p[0], a_p[1]);
+        proof.B = Pairing.G2Point([b[0][0], b[0][1]], [b[1][0], b[1][1]]);
+        proof.Bp = Pairing.G1Point(b_p[0], b_p[1]);
+        proof.C = Pairing.G1Point(c[0], c[1]);
+        proof.Cp = Pairing.G1Point(c_p[0], c_p[1]);
+        proof.H = Pairing.G1Point(h[0], h[1]);
+        proof.K = Pairing.G1Point(k[0], k[1]);
+        uint256[] memory inputValues = new uint256[]({{.Inputs}});
+{{- if .Inputs}}
+        for (uint256 i = 0; i < input.length; i++) {
+            inputValues[i] = input[i];
+        }
+{{- end}}
+        return verify(inputValues, proof) == 0;
+    }
+}
+`))
@@ -0,0 +1,153 @@
+package verifier
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWriteSolidityVerifier(t *testing.T) {
+	vk, _, _ := zokratesExample(t)
+	var contract bytes.Buffer
+	if err := WriteSolidityVerifier(&contract, vk, "BoardVerifier"); err != nil {
+		t.Fatal(err)
+	}
+	source := contract.String()
+
+	// every point of the key is embedded in the precompile layout
+	z := g2ToJSON(vk.Z)
+	for _, expected := range []string{
+		"contract BoardVerifier {",
+		"uint256[6] memory input",
+		"vk.IC = new Pairing.G1Point[](7);",
+		"vk.IC[6] = Pairing.G1Point(uint256(" + g1ToJSON(vk.IC[6])[0] + "), " + g1ToJSON(vk.IC[6])[1] + ");",
+		"[uint256(" + z[0][0] + "), " + z[0][1] + "]",
+		"[uint256(" + z[1][0] + "), " + z[1][1] + "]",
+	} {
+		if !strings.Contains(source, expected) {
+			t.Fatalf("contract does not contain %q", expected)
+		}
+	}
+	if strings.Count(source, "{") != strings.Count(source, "}") {
+		t.Fatal("braces are not balanced")
+	}
+
+	// a key without public inputs has no input argument
+	vk.IC = vk.IC[:1]
+	contract.Reset()
+	if err := WriteSolidityVerifier(&contract, vk, "Verifier"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(contract.String(), "memory input\n") {
+		t.Fatal("empty input array is declared")
+	}
+
+	if err := WriteSolidityVerifier(&contract, vk, "Bad Name"); err == nil {
+		t.Fatal("invalid contract name is accepted")
+	}
+	if err := WriteSolidityVerifier(&contract, &VerifyingKey{}, "Verifier"); err != ErrInvalidVerifyingKey {
+		t.Fatalf("expected %v, got %v", ErrInvalidVerifyingKey, err)
+	}
+}
+
+var (
+	// verifyProofParameters matches the parameter list of verifyProof
+	verifyProofParameters = regexp.MustCompile(`(?s)function verifyProof\((.*?)\) public`)
+	solidityParameter     = regexp.MustCompile(`^(uint256(?:\[\d+\])+) memory (\w+)$`)
+	solidityDimension     = regexp.MustCompile(`\[(\d+)\]`)
+)
+
+// TestSolidityVerifierABI checks that the arguments of verifyProof take
+// the calldata words VerifyProofCalldata writes for them
+func TestSolidityVerifierABI(t *testing.T) {
+	vk, proof, witness := zokratesExample(t)
+	var contract bytes.Buffer
+	if err := WriteSolidityVerifier(&contract, vk, "Verifier"); err != nil {
+		t.Fatal(err)
+	}
+	match := verifyProofParameters.FindStringSubmatch(contract.String())
+	if match == nil {
+		t.Fatal("verifyProof is not found")
+	}
+	var types, names []string
+	for _, parameter := range strings.Split(match[1], ",") {
+		typeAndName := solidityParameter.FindStringSubmatch(strings.TrimSpace(parameter))
+		if typeAndName == nil {
+			t.Fatalf("unexpected parameter %q", parameter)
+		}
+		types = append(types, typeAndName[1])
+		names = append(names, typeAndName[2])
+	}
+	signature := "verifyProof(" + strings.Join(types, ",") + ")"
+	if signature != verifyProofSignature(len(witness)) {
+		t.Fatalf("contract declares %s, calldata is encoded for %s", signature, verifyProofSignature(len(witness)))
+	}
+
+	calldata, err := VerifyProofCalldata(proof, witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(calldata[:selectorSize], functionSelector(signature)) {
+		t.Fatal("selector differs")
+	}
+	inputs, _ := EncodeWitness(witness)
+	expected := map[string][]byte{
+		"a": proof.A.Marshal(), "a_p": proof.Ap.Marshal(), "b": proof.B.Marshal(), "b_p": proof.Bp.Marshal(),
+		"c": proof.C.Marshal(), "c_p": proof.Cp.Marshal(), "h": proof.H.Marshal(), "k": proof.K.Marshal(),
+		"input": inputs,
+	}
+	// all the arguments are static arrays, so they are encoded in place one by one
+	offset := selectorSize
+	for i, typ := range types {
+		size := abiWordSize
+		for _, dimension := range solidityDimension.FindAllStringSubmatch(typ, -1) {
+			n, _ := strconv.Atoi(dimension[1])
+			size *= n
+		}
+		if offset+size > len(calldata) || !bytes.Equal(calldata[offset:offset+size], expected[names[i]]) {
+			t.Fatalf("argument %s is not at offset %d of calldata", names[i], offset)
+		}
+		offset += size
+	}
+	if offset != len(calldata) {
+		t.Fatalf("calldata has %d bytes, arguments take %d", len(calldata), offset)
+	}
+}
+
+// TestSolidityVerifierCompiles compiles the contract if solc is installed
+func TestSolidityVerifierCompiles(t *testing.T) {
+	solc, err := exec.LookPath("solc")
+	if err != nil {
+		t.Skip("solc is not installed")
+	}
+	vk, _, witness := zokratesExample(t)
+	dir, err := ioutil.TempDir("", "solidity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var contract bytes.Buffer
+	if err := WriteSolidityVerifier(&contract, vk, "Verifier"); err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(dir, "Verifier.sol")
+	if err := ioutil.WriteFile(filename, contract.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(solc, "--hashes", filename).CombinedOutput()
+	if err != nil {
+		t.Fatalf("contract does not compile: %v\n%s", err, out)
+	}
+	signature := verifyProofSignature(len(witness))
+	expected := hex.EncodeToString(functionSelector(signature)) + ": " + signature
+	if !strings.Contains(string(out), expected) {
+		t.Fatalf("compiled contract has no %q:\n%s", expected, out)
+	}
+}
@@ -0,0 +1,92 @@
+package verifier
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Every point from an untrusted source goes through UnmarshalG1 or UnmarshalG2,
+// either directly or via NewG1 and NewG2, so parsers of all formats
+// reject malformed points with the same errors
+
+var (
+	// ErrCoordinateOutOfRange is returned if a coordinate is negative or not less than P
+	ErrCoordinateOutOfRange = errors.New("Point coordinate is out of the field range")
+	// ErrNotOnCurve is returned if coordinates do not satisfy the curve equation
+	ErrNotOnCurve = errors.New("Point is not on the curve")
+	// ErrNotInSubgroup is returned if G2 point is on the twist, but not in the group of order Order.
+	// G1 has cofactor 1, so every G1 point on the curve is in the group
+	ErrNotInSubgroup = errors.New("Point is not in the prime order subgroup")
+	// ErrIdentityPoint is returned for the point at infinity if it is rejected by WithIdentityRejection
+	ErrIdentityPoint = errors.New("Point at infinity is not allowed")
+)
+
+func checkCoordinates(data []byte) error {
+	for i := 0; i < len(data); i += 32 {
+		if new(big.Int).SetBytes(data[i:i+32]).Cmp(P) >= 0 {
+			return ErrCoordinateOutOfRange
+		}
+	}
+	return nil
+}
+
+// UnmarshalG1 parses 64 bytes x, y of a G1 point, all zeros is the point at infinity
+func UnmarshalG1(data []byte) (*G1, error) {
+	if len(data) != g1Size {
+		return nil, errors.New("G1 point should be 64 bytes long")
+	}
+	if err := checkCoordinates(data); err != nil {
+		return nil, err
+	}
+	point := new(G1)
+	if _, err := point.Unmarshal(data); err != nil {
+		return nil, ErrNotOnCurve
+	}
+	return point, nil
+}
+
+// UnmarshalG2 parses 128 bytes x.c1, x.c0, y.c1, y.c0 of a G2 point,
+// all zeros is the point at infinity
+func UnmarshalG2(data []byte) (*G2, error) {
+	if len(data) != g2Size {
+		return nil, errors.New("G2 point should be 128 bytes long")
+	}
+	if err := checkCoordinates(data); err != nil {
+		return nil, err
+	}
+	if !isInfinity(data) {
+		word := func(i int) *big.Int { return new(big.Int).SetBytes(data[i*32 : (i+1)*32]) }
+		if !onTwist(fp2{word(1), word(0)}, fp2{word(3), word(2)}) {
+			return nil, ErrNotOnCurve
+		}
+	}
+	// bn256 checks both the curve equation and the order of the point,
+	// the former has passed already
+	point := new(G2)
+	if _, err := point.Unmarshal(data); err != nil {
+		return nil, ErrNotInSubgroup
+	}
+	return point, nil
+}
+
+// coordinateBytes pads a coordinate to 32 bytes, checking its range
+func coordinateBytes(c *big.Int) ([]byte, error) {
+	if c == nil || c.Sign() < 0 || c.Cmp(P) >= 0 {
+		return nil, ErrCoordinateOutOfRange
+	}
+	return padBigInt(c)
+}
+
+// checkIdentity rejects proofs with points at infinity, an honest prover
+// produces them only with negligible probability
+func (proof *Proof) checkIdentity() error {
+	for _, p := range []*G1{proof.A, proof.Ap, proof.Bp, proof.C, proof.Cp, proof.H, proof.K} {
+		if isInfinity(p.Marshal()) {
+			return ErrIdentityPoint
+		}
+	}
+	if isInfinity(proof.B.Marshal()) {
+		return ErrIdentityPoint
+	}
+	return nil
+}
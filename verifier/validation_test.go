@@ -0,0 +1,109 @@
+package verifier
+
+import (
+	"math/big"
+	"testing"
+)
+
+func (a fp2) exp(e *big.Int) fp2 {
+	result := fp2{big.NewInt(1), big.NewInt(0)}
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		result = result.mul(result)
+		if e.Bit(i) == 1 {
+			result = result.mul(a)
+		}
+	}
+	return result
+}
+
+// sqrt is the square root for p = 3 mod 4, see https://eprint.iacr.org/2012/685.pdf, algorithm 9
+func (a fp2) sqrt() (fp2, bool) {
+	a1 := a.exp(new(big.Int).Rsh(new(big.Int).Sub(P, big.NewInt(3)), 2))
+	alpha := a1.mul(a1).mul(a)
+	x0 := a1.mul(a)
+	minusOne := new(big.Int).Sub(P, big.NewInt(1))
+	var root fp2
+	if alpha[0].Cmp(minusOne) == 0 && alpha[1].Sign() == 0 {
+		root = fp2{big.NewInt(0), big.NewInt(1)}.mul(x0)
+	} else {
+		b := alpha.add(fp2{big.NewInt(1), big.NewInt(0)}).exp(new(big.Int).Rsh(minusOne, 1))
+		root = b.mul(x0)
+	}
+	return root, root.mul(root).equal(a)
+}
+
+// twistPointOutOfSubgroup finds a random point on the twist y^2 = x^3 + 3/(9+i),
+// it's not in the subgroup with overwhelming probability, as the cofactor is large
+func twistPointOutOfSubgroup(t *testing.T) (x, y fp2) {
+	for {
+		x = fp2{randomScalar(t), randomScalar(t)}
+		y, ok := x.mul(x).mul(x).add(twistB).sqrt()
+		if ok {
+			return x, y
+		}
+	}
+}
+
+func TestPointValidation(t *testing.T) {
+	if _, err := NewG1(P, big.NewInt(2)); err != ErrCoordinateOutOfRange {
+		t.Fatalf("expected %v, got %v", ErrCoordinateOutOfRange, err)
+	}
+	if _, err := NewG1(big.NewInt(-1), big.NewInt(2)); err != ErrCoordinateOutOfRange {
+		t.Fatalf("expected %v, got %v", ErrCoordinateOutOfRange, err)
+	}
+	// too large coordinates used to be misaligned
+	huge := new(big.Int).Lsh(big.NewInt(1), 300)
+	if _, err := NewG1(huge, big.NewInt(2)); err != ErrCoordinateOutOfRange {
+		t.Fatalf("expected %v, got %v", ErrCoordinateOutOfRange, err)
+	}
+	if _, err := NewG1(big.NewInt(1), big.NewInt(3)); err != ErrNotOnCurve {
+		t.Fatalf("expected %v, got %v", ErrNotOnCurve, err)
+	}
+
+	x, y := twistPointOutOfSubgroup(t)
+	data := make([]byte, 0, 128)
+	for _, c := range []*big.Int{x[1], x[0], y[1], y[0]} {
+		word, _ := padBigInt(c)
+		data = append(data, word...)
+	}
+	if _, err := NewG2([2]*big.Int{x[1], x[0]}, [2]*big.Int{x[1], x[0]}); err != ErrNotOnCurve {
+		t.Fatalf("expected %v, got %v", ErrNotOnCurve, err)
+	}
+	if _, err := NewG2([2]*big.Int{x[1], x[0]}, [2]*big.Int{y[1], y[0]}); err != ErrNotInSubgroup {
+		t.Fatalf("expected %v, got %v", ErrNotInSubgroup, err)
+	}
+	pairing := append(GetG1Base().Marshal(), data...)
+	if _, err := PairingCheckBytes(pairing); err != ErrNotInSubgroup {
+		t.Fatalf("expected %v, got %v", ErrNotInSubgroup, err)
+	}
+	if _, err := UnmarshalG2(GetG2Base().Marshal()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the same errors come from the parsers
+	_, proof, _ := zokratesExample(t)
+	binary, _ := proof.MarshalBinary()
+	copy(binary[64:96], P.Bytes())
+	if err := new(Proof).UnmarshalBinary(binary); err != ErrCoordinateOutOfRange {
+		t.Fatalf("expected %v, got %v", ErrCoordinateOutOfRange, err)
+	}
+}
+
+func TestIdentityRejection(t *testing.T) {
+	vk, proof, witness := zokratesExample(t)
+	if err := Verify(vk, proof, witness, WithIdentityRejection()); err != nil {
+		t.Fatal(err)
+	}
+	infinity := *proof
+	infinity.H = new(G1).ScalarBaseMult(big.NewInt(0))
+	if err := Verify(vk, &infinity, witness); err != ErrDivisibility {
+		t.Fatalf("expected %v, got %v", ErrDivisibility, err)
+	}
+	if err := Verify(vk, &infinity, witness, WithIdentityRejection()); err != ErrIdentityPoint {
+		t.Fatalf("expected %v, got %v", ErrIdentityPoint, err)
+	}
+	err := BatchVerify(vk, []Proof{*proof, infinity}, []Witness{witness, witness}, WithIdentityRejection())
+	if batchErr, ok := err.(*BatchError); !ok || len(batchErr.Invalid) != 1 || batchErr.Invalid[0] != 1 {
+		t.Fatalf("expected the second proof to be invalid, got %v", err)
+	}
+}
@@ -1,13 +1,13 @@
 package verifier
 
 import (
-	"bytes"
+	"context"
 	"errors"
+	"io"
 	"math/big"
-	"math/rand"
 	"strings"
 
-	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	"github.com/shamatar/go-snarks/curve"
 )
 
 // verify a Pinocchio snark
@@ -19,9 +19,9 @@ type VerifyingKey struct {
 	A          *G2
 	B          *G1
 	C          *G2
-	gamma      *G2
-	gammaBeta1 *G1
-	gammaBeta2 *G2
+	Gamma      *G2
+	GammaBeta1 *G1
+	GammaBeta2 *G2
 	Z          *G2
 	IC         []*G1 // set of G1 point to multiply a witness on
 }
@@ -76,14 +76,15 @@ func base10bi(s string) (*big.Int, error) {
 }
 
 func NewG1(xCoord, yCoord *big.Int) (*G1, error) {
-	point := new(G1)
-	xMarshalled, _ := padBigInt(xCoord)
-	yMarshalled, _ := padBigInt(yCoord)
-	_, err := point.Unmarshal(append(xMarshalled, yMarshalled...))
-	if err != nil {
-		return nil, err
+	marshalled := make([]byte, 0, 64)
+	for _, c := range []*big.Int{xCoord, yCoord} {
+		b, err := coordinateBytes(c)
+		if err != nil {
+			return nil, err
+		}
+		marshalled = append(marshalled, b...)
 	}
-	return point, nil
+	return UnmarshalG1(marshalled)
 }
 
 func NewG1FromStrings(xCoord, yCoord string, radix int) (*G1, error) {
@@ -112,19 +113,15 @@ func NewG1FromStrings(xCoord, yCoord string, radix int) (*G1, error) {
 }
 
 func NewG2(aCoords, bCoords [2]*big.Int) (*G2, error) {
-	point := new(G2)
-	aMarshalled, _ := padBigInt(aCoords[0])
-	bMarshalled, _ := padBigInt(aCoords[1])
-	cMarshalled, _ := padBigInt(bCoords[0])
-	dMarshalled, _ := padBigInt(bCoords[1])
-	marshalled := append(aMarshalled, bMarshalled...)
-	marshalled = append(marshalled, cMarshalled...)
-	marshalled = append(marshalled, dMarshalled...)
-	_, err := point.Unmarshal(marshalled)
-	if err != nil {
-		return nil, err
+	marshalled := make([]byte, 0, 128)
+	for _, c := range []*big.Int{aCoords[0], aCoords[1], bCoords[0], bCoords[1]} {
+		b, err := coordinateBytes(c)
+		if err != nil {
+			return nil, err
+		}
+		marshalled = append(marshalled, b...)
 	}
-	return point, nil
+	return UnmarshalG2(marshalled)
 }
 
 func NewG2FromStrings(aCoords, bCoords [2]string, radix int) (*G2, error) {
@@ -188,154 +185,36 @@ func GetG2Base() *G2 {
 	return generator
 }
 
+// accumulateWitness computes IC[0] + sum(witness[i] * IC[i+1]) on the curve of the points
+func accumulateWitness(c curve.Curve, ic []curve.G1, witness Witness, workers int) (curve.G1, error) {
+	sum, err := multiExp(c, ic[1:len(witness)+1], witness, workers)
+	if err != nil {
+		return nil, err
+	}
+	return c.AddG1(sum, ic[0]), nil
+}
+
 // naiveSplitVerification computes A LOT of pairing
 // follows the ZoKrates logic for verification in smart-contracts
 // where randomness is not available
 func naiveSplitVerification(witness Witness, proof *Proof, vk *VerifyingKey) error {
-	if len(witness)+1 != len(vk.IC) {
-		return errors.New("Invalid length of the witness")
-	}
-	G2Base := GetG2Base()
-	witnessAccululator := new(G1).Set(vk.IC[0])
-	temp := new(G1)
-	for i, w := range witness {
-		temp.ScalarMult(vk.IC[i+1], w)
-		witnessAccululator.Add(temp, witnessAccululator)
-	}
-	// e(proof.A, vk.A) == e(-proof.Ap, G2)
-	success := PairingCheck([]*G1{proof.A, temp.Neg(proof.Ap)}, []*G2{vk.A, G2Base})
-	if !success {
-		return errors.New("First pairing equation has failed")
-	}
-	// e(vk.B, proof.B) + e(-proof.Bp, G2)
-	success = PairingCheck([]*G1{vk.B, temp.Neg(proof.Bp)}, []*G2{proof.B, G2Base})
-	if !success {
-		return errors.New("Second pairing equation has failed")
-	}
-	// e(proof.C, vk.C) + e(-proof.Cp, G2)
-	success = PairingCheck([]*G1{proof.C, temp.Neg(proof.Cp)}, []*G2{vk.C, G2Base})
-	if !success {
-		return errors.New("Third pairing equation has failed")
-	}
-
-	// e(proof.K, vk.gamma) + e(- witnessAccumulator - proof.A - proof.C, vk.gammaBeta2) + e(-vk.gammaBeta1, proof.B)
-	t := new(G1)
-	t.Add(witnessAccululator, proof.A)
-	t.Add(t, proof.C)
-	t.Neg(t)
-
-	success = PairingCheck([]*G1{proof.K, t, temp.Neg(vk.gammaBeta1)}, []*G2{vk.gamma, vk.gammaBeta2, proof.B})
-	if !success {
-		return errors.New("Fourth pairing equation has failed")
-	}
-
-	// e(witnessAccumulator + proof.A, proof.B) + e(- proof.H, vk.Z) + e(-proof.C, G2)
-	u := new(G1)
-	u.Add(witnessAccululator, proof.A)
-
-	success = PairingCheck([]*G1{u, temp.Neg(proof.H), new(G1).Neg(proof.C)}, []*G2{proof.B, vk.Z, G2Base})
-	if !success {
-		return errors.New("Fifth pairing equation has failed")
-	}
-	return nil
+	return prepare(vk).splitVerification(context.Background(), witness, proof.onBN254(), 1)
 }
 
-// agregatedVerification takes some entropy and tried to run ONE pairing check
-// if sum of pairing with arbitrary coefficients holds than most likely each of those holds
-func agregatedVerification(witness Witness, proof *Proof, vk *VerifyingKey) error {
-	if len(witness)+1 != len(vk.IC) {
-		return errors.New("Invalid length of the witness")
-	}
-	G2Base := GetG2Base()
-	witnessAccululator := new(G1).Set(vk.IC[0])
-	temp := new(G1)
+// coefficientSize is the size of random coefficients in bytes,
+// so a forged proof passes an aggregated check with probability 2^-128
+const coefficientSize = 16
 
-	for i, w := range witness {
-		temp.ScalarMult(vk.IC[i+1], w)
-		witnessAccululator.Add(temp, witnessAccululator)
-	}
-	// grab some entopy for pairing checks
-	entropy := make([]*big.Int, 5)
-	for i := range entropy {
-		slice := make([]byte, 32)
-		rand.Read(slice)
-		entropy[i] = new(big.Int).SetBytes(slice)
-	}
-
-	pairings := make([]*GT, 5)
-
-	// e(proof.A, vk.A) + e(-proof.Ap, G2)
-	pair := bn256.Miller(proof.A, vk.A)
-	pair = pair.Add(pair, bn256.Miller(temp.Neg(proof.Ap), G2Base))
-	pairings[0] = pair
-	// emptyGT := new(GT)
-	// emptyGT.Set(pair)
-	// emptyGT.Finalize()
-	// if bytes.Compare(emptyGT.Marshal(), IdentityBytes) != 0 {
-	// 	return errors.New("Pairing check has failed")
-	// }
-
-	// e(vk.B, proof.B) + e(-proof.Bp, G2)
-	pair = bn256.Miller(vk.B, proof.B)
-	pair = pair.Add(pair, bn256.Miller(temp.Neg(proof.Bp), G2Base))
-	pairings[1] = pair
-	// emptyGT.Set(pair)
-	// emptyGT.Finalize()
-	// if bytes.Compare(emptyGT.Marshal(), IdentityBytes) != 0 {
-	// 	return errors.New("Pairing check has failed")
-	// }
-
-	// e(proof.C, vk.C) + e(-proof.Cp, G2)
-	pair = bn256.Miller(proof.C, vk.C)
-	pair = pair.Add(pair, bn256.Miller(temp.Neg(proof.Cp), G2Base))
-	pairings[2] = pair
-	// emptyGT.Set(pair)
-	// emptyGT.Finalize()
-	// if bytes.Compare(emptyGT.Marshal(), IdentityBytes) != 0 {
-	// 	return errors.New("Pairing check has failed")
-	// }
-
-	// e(proof.K, vk.gamma) + e(- witnessAccumulator - proof.A - proof.C, vk.gammaBeta2) + e(-vk.gammaBeta1, proof.B)
-	t := new(G1)
-	t.Add(witnessAccululator, proof.A)
-	t.Add(t, proof.C)
-	t.Neg(t)
-	pair = bn256.Miller(proof.K, vk.gamma)
-	pair = pair.Add(pair, bn256.Miller(t, vk.gammaBeta2))
-	pair = pair.Add(pair, bn256.Miller(temp.Neg(vk.gammaBeta1), proof.B))
-	pairings[3] = pair
-	// emptyGT.Set(pair)
-	// emptyGT.Finalize()
-	// if bytes.Compare(emptyGT.Marshal(), IdentityBytes) != 0 {
-	// 	return errors.New("Pairing check has failed")
-	// }
-
-	// e(witnessAccumulator + proof.A, proof.B) + e(- proof.H, vk.Z) + e(-proof.C, G2)
-	u := new(G1)
-	u.Add(witnessAccululator, proof.A)
-
-	pair = bn256.Miller(u, proof.B)
-	pair = pair.Add(pair, bn256.Miller(temp.Neg(proof.H), vk.Z))
-	pair = pair.Add(pair, bn256.Miller(new(G1).Neg(proof.C), G2Base))
-	pairings[4] = pair
-	// emptyGT.Set(pair)
-	// emptyGT.Finalize()
-	// if bytes.Compare(emptyGT.Marshal(), IdentityBytes) != 0 {
-	// 	return errors.New("Pairing check has failed")
-	// }
-
-	linearCombination := new(GT).Set(pairings[0])
-	for i := 1; i < len(pairings); i++ {
-		linearCombination.Add(linearCombination, pairings[i].ScalarMult(pairings[i], entropy[i]))
-		// emptyGT.Set(linearCombination)
-		// emptyGT.Finalize()
-		// if bytes.Compare(emptyGT.Marshal(), IdentityBytes) != 0 {
-		// 	return errors.New("Pairing check has failed")
-		// }
+// randomCoefficient reads a non-zero coefficient for linear combination of pairings
+func randomCoefficient(random io.Reader) (*big.Int, error) {
+	slice := make([]byte, coefficientSize)
+	if _, err := io.ReadFull(random, slice); err != nil {
+		return nil, err
 	}
-	linearCombination.Finalize()
-	if bytes.Compare(linearCombination.Marshal(), IdentityBytes) != 0 {
-		return errors.New("Pairing check has failed")
+	coefficient := new(big.Int).SetBytes(slice)
+	coefficient.Mod(coefficient, Order)
+	if coefficient.Sign() == 0 {
+		return nil, errors.New("Random source gave a zero coefficient")
 	}
-	return nil
+	return coefficient, nil
 }
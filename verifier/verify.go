@@ -0,0 +1,163 @@
+package verifier
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// Strategy selects the way pairing equations of a Pinocchio proof are checked
+type Strategy int
+
+const (
+	// SplitStrategy checks every one of the five equations by a separate
+	// pairing check, same as the ZoKrates smart-contract does
+	SplitStrategy Strategy = iota
+	// AggregateStrategy combines all the equations with random coefficients,
+	// merges pairings on the same G2 point and does only ONE final exponentiation
+	AggregateStrategy
+)
+
+var (
+	// ErrInvalidVerifyingKey is returned if some of the key points are missing
+	ErrInvalidVerifyingKey = errors.New("Verifying key is incomplete")
+	// ErrInvalidProof is returned if some of the proof points are missing
+	ErrInvalidProof = errors.New("Proof is incomplete")
+	// ErrInvalidWitnessLength is returned if number of public inputs does not match the key
+	ErrInvalidWitnessLength = errors.New("Invalid length of the witness")
+	// ErrWitnessOutOfRange is returned if public input is negative or not less than the group order
+	ErrWitnessOutOfRange = errors.New("Witness element is out of the field range")
+
+	// ErrKnowledgeA is e(proof.A, vk.A) == e(proof.Ap, G2) failure
+	ErrKnowledgeA = errors.New("First pairing equation has failed")
+	// ErrKnowledgeB is e(vk.B, proof.B) == e(proof.Bp, G2) failure
+	ErrKnowledgeB = errors.New("Second pairing equation has failed")
+	// ErrKnowledgeC is e(proof.C, vk.C) == e(proof.Cp, G2) failure
+	ErrKnowledgeC = errors.New("Third pairing equation has failed")
+	// ErrSameCoefficients is the failure of the equation involving proof.K,
+	// so A, B and C were not built from the same coefficients
+	ErrSameCoefficients = errors.New("Fourth pairing equation has failed")
+	// ErrDivisibility is the failure of the QAP divisibility equation involving proof.H
+	ErrDivisibility = errors.New("Fifth pairing equation has failed")
+	// ErrAggregateCheck is returned if aggregated check has failed,
+	// but none of the split equations did
+	ErrAggregateCheck = errors.New("Pairing check has failed")
+)
+
+type options struct {
+	strategy       Strategy
+	random         io.Reader
+	workers        int
+	rejectIdentity bool
+	reduceWitness  bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{strategy: SplitStrategy, random: rand.Reader, workers: 1}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Option changes the default behaviour of Verify
+type Option func(*options)
+
+// WithStrategy selects a pairing check strategy, SplitStrategy is the default
+func WithStrategy(strategy Strategy) Option {
+	return func(o *options) {
+		o.strategy = strategy
+	}
+}
+
+// WithRandom replaces crypto/rand as a source of coefficients for
+// aggregated and batch verification. It's useful for deterministic tests,
+// but the source should never be predictable for a prover
+func WithRandom(random io.Reader) Option {
+	return func(o *options) {
+		o.random = random
+	}
+}
+
+// WithWorkers sets the number of goroutines that compute Miller loops and
+// the public input accumulator of every strategy and of batch verification.
+// The default is 1, so verification does not spawn goroutines
+func WithWorkers(workers int) Option {
+	return func(o *options) {
+		o.workers = workers
+	}
+}
+
+// WithIdentityRejection makes proofs with a point at infinity fail with ErrIdentityPoint
+func WithIdentityRejection() Option {
+	return func(o *options) {
+		o.rejectIdentity = true
+	}
+}
+
+// WithWitnessReduction takes public inputs mod Order instead of failing with
+// ErrWitnessOutOfRange. Inputs that differ by a multiple of Order are then
+// accepted by the same proof, so it is only safe if they are never used
+// as identifiers outside of the circuit
+func WithWitnessReduction() Option {
+	return func(o *options) {
+		o.reduceWitness = true
+	}
+}
+
+// Verify checks a Pinocchio proof for a set of public inputs.
+// Returns nil if proof is valid, otherwise one of Err* values above.
+// If aggregated check fails the split one is run to tell which equation is broken.
+func Verify(vk *VerifyingKey, proof *Proof, inputs Witness, opts ...Option) error {
+	return VerifyContext(context.Background(), vk, proof, inputs, opts...)
+}
+
+// VerifyContext is Verify that gives up with the context error once the context is done
+func VerifyContext(ctx context.Context, vk *VerifyingKey, proof *Proof, inputs Witness, opts ...Option) error {
+	if err := vk.validate(); err != nil {
+		return err
+	}
+	return prepare(vk).VerifyContext(ctx, proof, inputs, opts...)
+}
+
+// checkWitness checks that there is a scalar for every IC point except the first one.
+// With reduce set out of range scalars are replaced by canonical ones in a copy
+func checkWitness(inputs Witness, icLength int, reduce bool) (Witness, error) {
+	return checkWitnessOf(inputs, icLength, Order, reduce)
+}
+
+func checkWitnessOf(inputs Witness, icLength int, order *big.Int, reduce bool) (Witness, error) {
+	if len(inputs)+1 != icLength {
+		return nil, ErrInvalidWitnessLength
+	}
+	if reduce {
+		return reduceWitness(inputs, order)
+	}
+	if err := checkWitnessRange(inputs, order); err != nil {
+		return nil, err
+	}
+	return inputs, nil
+}
+
+func (vk *VerifyingKey) validate() error {
+	if vk == nil || vk.A == nil || vk.B == nil || vk.C == nil || vk.Gamma == nil ||
+		vk.GammaBeta1 == nil || vk.GammaBeta2 == nil || vk.Z == nil || len(vk.IC) == 0 {
+		return ErrInvalidVerifyingKey
+	}
+	for _, p := range vk.IC {
+		if p == nil {
+			return ErrInvalidVerifyingKey
+		}
+	}
+	return nil
+}
+
+func (proof *Proof) validate() error {
+	if proof == nil || proof.A == nil || proof.Ap == nil || proof.B == nil || proof.Bp == nil ||
+		proof.C == nil || proof.Cp == nil || proof.K == nil || proof.H == nil {
+		return ErrInvalidProof
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package verifier
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+	mathrand "math/rand"
+	"os"
+	"testing"
+)
+
+// zokratesExample parses the ZoKrates example key and proof of the fibonacci circuit
+func zokratesExample(t testing.TB) (*VerifyingKey, *Proof, Witness) {
+	keyFile, err := os.Open("zokrates_verification.key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer keyFile.Close()
+	vk, err := ParseZoKratesVerifyingKey(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proofFile, err := os.Open("zokrates_proof.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer proofFile.Close()
+	proof, witness, err := ParseZoKratesProof(proofFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return vk, proof, witness
+}
+
+func TestVerify(t *testing.T) {
+	vk, proof, witness := zokratesExample(t)
+	for _, strategy := range []Strategy{SplitStrategy, AggregateStrategy} {
+		err := Verify(vk, proof, witness, WithStrategy(strategy))
+		if err != nil {
+			t.Fatalf("strategy %d: %v", strategy, err)
+		}
+	}
+}
+
+func TestVerifyReportsFailedEquation(t *testing.T) {
+	vk, proof, witness := zokratesExample(t)
+	witness[5] = big.NewInt(3)
+	for _, strategy := range []Strategy{SplitStrategy, AggregateStrategy} {
+		err := Verify(vk, proof, witness, WithStrategy(strategy))
+		if err != ErrSameCoefficients {
+			t.Fatalf("strategy %d: expected %v, got %v", strategy, ErrSameCoefficients, err)
+		}
+	}
+
+	vk, proof, witness = zokratesExample(t)
+	proof.Ap = proof.Cp
+	err := Verify(vk, proof, witness, WithStrategy(AggregateStrategy))
+	if err != ErrKnowledgeA {
+		t.Fatalf("expected %v, got %v", ErrKnowledgeA, err)
+	}
+}
+
+func TestVerifyValidatesInputs(t *testing.T) {
+	vk, proof, witness := zokratesExample(t)
+	if err := Verify(vk, proof, witness[:5]); err != ErrInvalidWitnessLength {
+		t.Fatalf("expected %v, got %v", ErrInvalidWitnessLength, err)
+	}
+	witness[0] = new(big.Int).Set(Order)
+	if err := Verify(vk, proof, witness); err != ErrWitnessOutOfRange {
+		t.Fatalf("expected %v, got %v", ErrWitnessOutOfRange, err)
+	}
+	witness[0] = big.NewInt(-1)
+	if err := Verify(vk, proof, witness); err != ErrWitnessOutOfRange {
+		t.Fatalf("expected %v, got %v", ErrWitnessOutOfRange, err)
+	}
+	proof.K = nil
+	if err := Verify(vk, proof, witness); err != ErrInvalidProof {
+		t.Fatalf("expected %v, got %v", ErrInvalidProof, err)
+	}
+	if err := Verify(&VerifyingKey{}, proof, witness); err != ErrInvalidVerifyingKey {
+		t.Fatalf("expected %v, got %v", ErrInvalidVerifyingKey, err)
+	}
+}
+
+func TestVerifyWithInjectedRandomness(t *testing.T) {
+	vk, proof, witness := zokratesExample(t)
+	random := mathrand.New(mathrand.NewSource(42))
+	err := Verify(vk, proof, witness, WithStrategy(AggregateStrategy), WithRandom(random))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// exhausted source of randomness is an error, not a pass
+	err = Verify(vk, proof, witness, WithStrategy(AggregateStrategy), WithRandom(bytes.NewReader(nil)))
+	if err != io.EOF {
+		t.Fatalf("expected %v, got %v", io.EOF, err)
+	}
+	err = BatchVerify(vk, []Proof{*proof}, []Witness{witness}, WithRandom(bytes.NewReader(nil)))
+	if err != io.EOF {
+		t.Fatalf("expected %v, got %v", io.EOF, err)
+	}
+}
+
+func TestRandomCoefficient(t *testing.T) {
+	random := mathrand.New(mathrand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		coefficient, err := randomCoefficient(random)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if coefficient.Sign() <= 0 || coefficient.BitLen() > coefficientSize*8 {
+			t.Fatalf("coefficient is out of range: %v", coefficient)
+		}
+	}
+	if _, err := randomCoefficient(bytes.NewReader(make([]byte, coefficientSize))); err == nil {
+		t.Fatal("zero coefficient is accepted")
+	}
+}
@@ -0,0 +1,125 @@
+package verifier
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Public inputs are elements of the scalar field, so only integers in [0, Order)
+// are accepted. Any other integer is congruent to one of them and would be
+// accepted by the same proof, which lets anybody change an input without a new proof
+
+func inRange(n *big.Int) bool {
+	return inRangeOf(n, Order)
+}
+
+func inRangeOf(n, order *big.Int) bool {
+	return n != nil && n.Sign() >= 0 && n.Cmp(order) < 0
+}
+
+// CheckWitness returns ErrWitnessOutOfRange if some of the inputs is nil,
+// negative or not less than Order
+func CheckWitness(witness Witness) error {
+	return checkWitnessRange(witness, Order)
+}
+
+func checkWitnessRange(witness Witness, order *big.Int) error {
+	for _, w := range witness {
+		if !inRangeOf(w, order) {
+			return ErrWitnessOutOfRange
+		}
+	}
+	return nil
+}
+
+// ReduceWitness returns a copy of the witness with every input taken mod Order
+func ReduceWitness(witness Witness) (Witness, error) {
+	return reduceWitness(witness, Order)
+}
+
+func reduceWitness(witness Witness, order *big.Int) (Witness, error) {
+	reduced := make(Witness, len(witness))
+	for i, w := range witness {
+		if w == nil {
+			return nil, ErrWitnessOutOfRange
+		}
+		if inRangeOf(w, order) {
+			reduced[i] = w
+			continue
+		}
+		reduced[i] = new(big.Int).Mod(w, order)
+	}
+	return reduced, nil
+}
+
+// WitnessFromBytes makes a witness of big-endian unsigned integers
+func WitnessFromBytes(values ...[]byte) (Witness, error) {
+	witness := make(Witness, len(values))
+	for i, v := range values {
+		n := new(big.Int).SetBytes(v)
+		if !inRange(n) {
+			return nil, fmt.Errorf("Invalid input %d: %w", i, ErrWitnessOutOfRange)
+		}
+		witness[i] = n
+	}
+	return witness, nil
+}
+
+// DecodeWitness is the reverse of EncodeWitness
+func DecodeWitness(data []byte) (Witness, error) {
+	if len(data)%abiWordSize != 0 {
+		return nil, fmt.Errorf("Length %d is not a multiple of %d", len(data), abiWordSize)
+	}
+	words := make([][]byte, len(data)/abiWordSize)
+	for i := range words {
+		words[i] = data[i*abiWordSize : (i+1)*abiWordSize]
+	}
+	return WitnessFromBytes(words...)
+}
+
+// WitnessFromUint64 makes a witness of small integers, which are always in range
+func WitnessFromUint64(values ...uint64) Witness {
+	witness := make(Witness, len(values))
+	for i, v := range values {
+		witness[i] = new(big.Int).SetUint64(v)
+	}
+	return witness
+}
+
+// WitnessFromBools makes a witness of 0 and 1, for example of board cells
+func WitnessFromBools(values ...bool) Witness {
+	witness := make(Witness, len(values))
+	for i, v := range values {
+		if v {
+			witness[i] = big.NewInt(1)
+		} else {
+			witness[i] = big.NewInt(0)
+		}
+	}
+	return witness
+}
+
+// WitnessFromStrings makes a witness of decimal or 0x prefixed hex field elements
+func WitnessFromStrings(values ...string) (Witness, error) {
+	witness := make(Witness, len(values))
+	for i, v := range values {
+		n, err := parseScalar(v)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid input %d: %v", i, err)
+		}
+		if !inRange(n) {
+			return nil, fmt.Errorf("Invalid input %d: %w", i, ErrWitnessOutOfRange)
+		}
+		witness[i] = n
+	}
+	return witness, nil
+}
+
+// parseScalar parses decimal or 0x prefixed hex string without a range check
+func parseScalar(s string) (*big.Int, error) {
+	if strings.HasPrefix(s, "0x") {
+		return base16bi(s)
+	}
+	return base10bi(s)
+}
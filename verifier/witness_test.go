@@ -0,0 +1,105 @@
+package verifier
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestWitnessReduction(t *testing.T) {
+	vk, proof, witness := zokratesExample(t)
+	// 1 + Order and 2 - Order alias inputs of the valid proof
+	witness[1] = new(big.Int).Add(witness[1], Order)
+	witness[5] = new(big.Int).Sub(witness[5], Order)
+	for _, strategy := range []Strategy{SplitStrategy, AggregateStrategy} {
+		if err := Verify(vk, proof, witness, WithStrategy(strategy)); err != ErrWitnessOutOfRange {
+			t.Fatalf("strategy %d: expected %v, got %v", strategy, ErrWitnessOutOfRange, err)
+		}
+		if err := Verify(vk, proof, witness, WithStrategy(strategy), WithWitnessReduction()); err != nil {
+			t.Fatalf("strategy %d: %v", strategy, err)
+		}
+	}
+	if witness[1].Cmp(Order) <= 0 {
+		t.Fatal("reduction has changed the caller's witness")
+	}
+
+	err := BatchVerify(vk, []Proof{*proof}, []Witness{witness})
+	if batchErr, ok := err.(*BatchError); !ok || len(batchErr.Invalid) != 1 {
+		t.Fatalf("expected an invalid proof, got %v", err)
+	}
+	if err := BatchVerify(vk, []Proof{*proof}, []Witness{witness}, WithWitnessReduction()); err != nil {
+		t.Fatal(err)
+	}
+
+	witness[0] = nil
+	if err := Verify(vk, proof, witness, WithWitnessReduction()); err != ErrWitnessOutOfRange {
+		t.Fatalf("expected %v, got %v", ErrWitnessOutOfRange, err)
+	}
+
+	groth16Witness := Witness{big.NewInt(1), big.NewInt(2)}
+	groth16VK, groth16Proof := groth16Example(t, groth16Witness)
+	groth16Witness[0] = new(big.Int).Add(groth16Witness[0], Order)
+	if err := VerifyGroth16(groth16VK, groth16Proof, groth16Witness); err != ErrWitnessOutOfRange {
+		t.Fatalf("expected %v, got %v", ErrWitnessOutOfRange, err)
+	}
+	if err := VerifyGroth16(groth16VK, groth16Proof, groth16Witness, WithWitnessReduction()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWitnessHelpers(t *testing.T) {
+	expected := Witness{big.NewInt(0), big.NewInt(1), big.NewInt(255)}
+	equal := func(witness Witness) bool {
+		for i := range witness {
+			if witness[i].Cmp(expected[i]) != 0 {
+				return false
+			}
+		}
+		return len(witness) == len(expected)
+	}
+
+	if !equal(WitnessFromUint64(0, 1, 255)) {
+		t.Fatal("uint64 witness is wrong")
+	}
+	fromStrings, err := WitnessFromStrings("0", "0x1", "255")
+	if err != nil || !equal(fromStrings) {
+		t.Fatalf("string witness is wrong: %v", err)
+	}
+	fromBytes, err := WitnessFromBytes(nil, []byte{0, 1}, []byte{255})
+	if err != nil || !equal(fromBytes) {
+		t.Fatalf("bytes witness is wrong: %v", err)
+	}
+	encoded, _ := EncodeWitness(expected)
+	decoded, err := DecodeWitness(encoded)
+	if err != nil || !equal(decoded) {
+		t.Fatalf("decoded witness is wrong: %v", err)
+	}
+	bools := WitnessFromBools(false, true)
+	if bools[0].Sign() != 0 || bools[1].Cmp(big.NewInt(1)) != 0 {
+		t.Fatal("bool witness is wrong")
+	}
+
+	if _, err := WitnessFromStrings("1", "-1"); !errors.Is(err, ErrWitnessOutOfRange) {
+		t.Fatalf("expected %v, got %v", ErrWitnessOutOfRange, err)
+	}
+	if _, err := WitnessFromStrings(Order.String()); !errors.Is(err, ErrWitnessOutOfRange) {
+		t.Fatalf("expected %v, got %v", ErrWitnessOutOfRange, err)
+	}
+	if _, err := WitnessFromStrings("0xfoo"); err == nil {
+		t.Fatal("invalid string is accepted")
+	}
+	if _, err := WitnessFromBytes(Order.Bytes()); !errors.Is(err, ErrWitnessOutOfRange) {
+		t.Fatalf("expected %v, got %v", ErrWitnessOutOfRange, err)
+	}
+	if _, err := DecodeWitness(encoded[1:]); err == nil {
+		t.Fatal("truncated witness is accepted")
+	}
+
+	reduced, err := ReduceWitness(Witness{new(big.Int).Add(Order, big.NewInt(5)), big.NewInt(-1)})
+	if err != nil || reduced[0].Cmp(big.NewInt(5)) != 0 || reduced[1].Cmp(new(big.Int).Sub(Order, big.NewInt(1))) != 0 {
+		t.Fatalf("reduced witness is wrong: %v", err)
+	}
+	if err := CheckWitness(reduced); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,255 @@
+package verifier
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ZoKrates verification.key is a list of lines "vk.name = value",
+// where G1 value is "0x.., 0x.." and G2 value is "[0x.., 0x..], [0x.., 0x..]"
+// with coordinates in the same order as for NewG2FromStrings.
+// PGHR13 key has a, b, c, gamma, gammaBeta1, gammaBeta2, z and IC,
+// G16 key has alpha, beta, gamma, delta and gamma_abc.
+// proof.json is {"proof": {...}, "inputs": [...]}
+
+type zokratesEntry struct {
+	line   int
+	values []string
+}
+
+type zokratesKey map[string]zokratesEntry
+
+// normalizeZoKratesName turns "vk.gamma_abc[0]" into "gammaabc[0]"
+func normalizeZoKratesName(name string) string {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "vk.")
+	return strings.ToLower(strings.Replace(name, "_", "", -1))
+}
+
+func readZoKratesKey(r io.Reader) (zokratesKey, error) {
+	entries := make(zokratesKey)
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		content := strings.TrimSpace(scanner.Text())
+		if content == "" {
+			continue
+		}
+		parts := strings.SplitN(content, "=", 2)
+		if len(parts) != 2 {
+			return nil, &ParseError{Line: line, Column: 1, Err: errors.New("expected name = value")}
+		}
+		value := strings.NewReplacer("[", " ", "]", " ", ",", " ").Replace(parts[1])
+		entries[normalizeZoKratesName(parts[0])] = zokratesEntry{line: line, values: strings.Fields(value)}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (k zokratesKey) entry(name string, size int) (zokratesEntry, error) {
+	entry, ok := k[name]
+	if !ok {
+		return entry, fmt.Errorf("Missing vk.%s", name)
+	}
+	if len(entry.values) != size {
+		return entry, &ParseError{Line: entry.line, Column: 1,
+			Err: fmt.Errorf("expected %d values for vk.%s, got %d", size, name, len(entry.values))}
+	}
+	return entry, nil
+}
+
+func (k zokratesKey) g1(name string) (*G1, error) {
+	entry, err := k.entry(name, 2)
+	if err != nil {
+		return nil, err
+	}
+	point, err := NewG1FromStrings(entry.values[0], entry.values[1], 16)
+	if err != nil {
+		return nil, &ParseError{Line: entry.line, Column: 1, Err: err}
+	}
+	return point, nil
+}
+
+func (k zokratesKey) g2(name string) (*G2, error) {
+	entry, err := k.entry(name, 4)
+	if err != nil {
+		return nil, err
+	}
+	point, err := NewG2FromStrings(
+		[2]string{entry.values[0], entry.values[1]},
+		[2]string{entry.values[2], entry.values[3]}, 16)
+	if err != nil {
+		return nil, &ParseError{Line: entry.line, Column: 1, Err: err}
+	}
+	return point, nil
+}
+
+// g1Vector reads "name.len()" and then "name[i]" points
+func (k zokratesKey) g1Vector(name string) ([]*G1, error) {
+	entry, err := k.entry(name+".len()", 1)
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.ParseUint(entry.values[0], 10, 64)
+	if err != nil || length == 0 || length > maxDomainSize+1 {
+		return nil, &ParseError{Line: entry.line, Column: 1, Err: fmt.Errorf("invalid length %q", entry.values[0])}
+	}
+	points := make([]*G1, length)
+	for i := range points {
+		points[i], err = k.g1(fmt.Sprintf("%s[%d]", name, i))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
+
+// ParseZoKratesVerifyingKey parses PGHR13 verification.key produced by ZoKrates
+func ParseZoKratesVerifyingKey(r io.Reader) (*VerifyingKey, error) {
+	k, err := readZoKratesKey(r)
+	if err != nil {
+		return nil, err
+	}
+	vk := &VerifyingKey{}
+	if vk.A, err = k.g2("a"); err != nil {
+		return nil, err
+	}
+	if vk.B, err = k.g1("b"); err != nil {
+		return nil, err
+	}
+	if vk.C, err = k.g2("c"); err != nil {
+		return nil, err
+	}
+	if vk.Gamma, err = k.g2("gamma"); err != nil {
+		return nil, err
+	}
+	if vk.GammaBeta1, err = k.g1("gammabeta1"); err != nil {
+		return nil, err
+	}
+	if vk.GammaBeta2, err = k.g2("gammabeta2"); err != nil {
+		return nil, err
+	}
+	if vk.Z, err = k.g2("z"); err != nil {
+		return nil, err
+	}
+	if vk.IC, err = k.g1Vector("ic"); err != nil {
+		return nil, err
+	}
+	return vk, nil
+}
+
+// ParseZoKratesGroth16VerifyingKey parses G16 verification.key produced by ZoKrates
+func ParseZoKratesGroth16VerifyingKey(r io.Reader) (*Groth16VerifyingKey, error) {
+	k, err := readZoKratesKey(r)
+	if err != nil {
+		return nil, err
+	}
+	vk := &Groth16VerifyingKey{}
+	if vk.Alpha, err = k.g1("alpha"); err != nil {
+		return nil, err
+	}
+	if vk.Beta, err = k.g2("beta"); err != nil {
+		return nil, err
+	}
+	if vk.Gamma, err = k.g2("gamma"); err != nil {
+		return nil, err
+	}
+	if vk.Delta, err = k.g2("delta"); err != nil {
+		return nil, err
+	}
+	if vk.IC, err = k.g1Vector("gammaabc"); err != nil {
+		return nil, err
+	}
+	return vk, nil
+}
+
+type zokratesProofJSON struct {
+	Proof  json.RawMessage   `json:"proof"`
+	Inputs []json.RawMessage `json:"inputs"`
+}
+
+type zokratesGroth16ProofJSON struct {
+	A jsonG1 `json:"a"`
+	B jsonG2 `json:"b"`
+	C jsonG1 `json:"c"`
+}
+
+// parseJSONInputs accepts hex and decimal strings as well as JSON numbers
+func parseJSONInputs(inputs []json.RawMessage) (Witness, error) {
+	witness := make(Witness, len(inputs))
+	for i, raw := range inputs {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			var number json.Number
+			if err := json.Unmarshal(raw, &number); err != nil {
+				return nil, fmt.Errorf("Invalid input %d", i)
+			}
+			value = number.String()
+		}
+		n, err := parseScalar(value)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid input %d: %v", i, err)
+		}
+		witness[i] = n
+	}
+	return witness, nil
+}
+
+func readZoKratesProof(r io.Reader) (*zokratesProofJSON, Witness, error) {
+	var decoded zokratesProofJSON
+	if err := json.NewDecoder(r).Decode(&decoded); err != nil {
+		return nil, nil, err
+	}
+	if len(decoded.Proof) == 0 {
+		return nil, nil, errors.New("Missing proof")
+	}
+	witness, err := parseJSONInputs(decoded.Inputs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &decoded, witness, nil
+}
+
+// ParseZoKratesProof parses PGHR13 proof.json produced by ZoKrates
+// and returns the proof with its public inputs
+func ParseZoKratesProof(r io.Reader) (*Proof, Witness, error) {
+	decoded, witness, err := readZoKratesProof(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	proof := new(Proof)
+	if err := proof.UnmarshalJSON(decoded.Proof); err != nil {
+		return nil, nil, err
+	}
+	return proof, witness, nil
+}
+
+// ParseZoKratesGroth16Proof parses G16 proof.json produced by ZoKrates
+// and returns the proof with its public inputs
+func ParseZoKratesGroth16Proof(r io.Reader) (*Groth16Proof, Witness, error) {
+	decoded, witness, err := readZoKratesProof(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var points zokratesGroth16ProofJSON
+	if err := json.Unmarshal(decoded.Proof, &points); err != nil {
+		return nil, nil, err
+	}
+	j := &jsonPoints{}
+	proof := &Groth16Proof{
+		A: j.g1(points.A),
+		B: j.g2(points.B),
+		C: j.g1(points.C),
+	}
+	if j.err != nil {
+		return nil, nil, j.err
+	}
+	return proof, witness, nil
+}
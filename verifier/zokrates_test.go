@@ -0,0 +1,106 @@
+package verifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestZoKratesImport(t *testing.T) {
+	keyFile, err := os.Open("zokrates_verification.key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer keyFile.Close()
+	vk, err := ParseZoKratesVerifyingKey(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proofFile, err := os.Open("zokrates_proof.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer proofFile.Close()
+	proof, witness, err := ParseZoKratesProof(proofFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(witness) != 6 || witness[5].Int64() != 2 {
+		t.Fatalf("inputs are not parsed: %v", witness)
+	}
+	if err := Verify(vk, proof, witness); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestZoKratesKeyErrors(t *testing.T) {
+	content, err := ioutil.ReadFile("zokrates_verification.key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(content), "\n")
+
+	// missing IC point
+	_, err = ParseZoKratesVerifyingKey(strings.NewReader(strings.Join(lines[:14], "\n")))
+	if err == nil || !strings.Contains(err.Error(), "vk.ic[6]") {
+		t.Fatalf("missing point is not reported: %v", err)
+	}
+
+	// malformed point is reported with its line
+	broken := append([]string{}, lines...)
+	broken[1] = "vk.b = 0x1, 0x3"
+	_, err = ParseZoKratesVerifyingKey(strings.NewReader(strings.Join(broken, "\n")))
+	if parseErr, ok := err.(*ParseError); !ok || parseErr.Line != 2 {
+		t.Fatalf("expected error at line 2, got %v", err)
+	}
+}
+
+func TestZoKratesGroth16Import(t *testing.T) {
+	witness := Witness{big.NewInt(3), big.NewInt(9)}
+	vk, proof := groth16Example(t, witness)
+
+	g1 := func(p *G1) string {
+		j := g1ToJSON(p)
+		return j[0] + ", " + j[1]
+	}
+	g2 := func(p *G2) string {
+		j := g2ToJSON(p)
+		return fmt.Sprintf("[%s, %s], [%s, %s]", j[0][0], j[0][1], j[1][0], j[1][1])
+	}
+	lines := []string{
+		"vk.alpha = " + g1(vk.Alpha),
+		"vk.beta = " + g2(vk.Beta),
+		"vk.gamma = " + g2(vk.Gamma),
+		"vk.delta = " + g2(vk.Delta),
+		"vk.gamma_abc.len() = 3",
+		"vk.gamma_abc[0] = " + g1(vk.IC[0]),
+		"vk.gamma_abc[1] = " + g1(vk.IC[1]),
+		"vk.gamma_abc[2] = " + g1(vk.IC[2]),
+	}
+	parsedVK, err := ParseZoKratesGroth16VerifyingKey(strings.NewReader(strings.Join(lines, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proofJSON, _ := json.Marshal(map[string]interface{}{
+		"proof": zokratesGroth16ProofJSON{
+			A: g1ToJSON(proof.A),
+			B: g2ToJSON(proof.B),
+			C: g1ToJSON(proof.C),
+		},
+		// ZoKrates writes hex, but decimal strings and numbers are accepted too
+		"inputs": []interface{}{"0x3", 9},
+	})
+	parsedProof, parsedWitness, err := ParseZoKratesGroth16Proof(strings.NewReader(string(proofJSON)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyGroth16(parsedVK, parsedProof, parsedWitness); err != nil {
+		t.Fatal(err)
+	}
+}